@@ -1,5 +1,12 @@
 package git
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jojomi/go-script/v2"
+)
+
 type Branch interface {
 	GetName() string
 	GetFullName() string // including remote if applicable
@@ -9,3 +16,39 @@ type Branch interface {
 	Delete() error
 	String() string
 }
+
+// make sure the interface is implemented
+var (
+	_ Branch = (*LocalBranch)(nil)
+	_ Branch = (*RemoteBranch)(nil)
+)
+
+// isMergedTo checks if the HEAD commit of source is reachable from target, which
+// may be local or remote branches in any combination.
+func isMergedTo(repository *Repository, source, target Branch) (bool, error) {
+	sourceHead, err := source.GetHeadCommit()
+	if err != nil {
+		return false, err
+	}
+	targetHead, err := target.GetHeadCommit()
+	if err != nil {
+		return false, err
+	}
+
+	// both have the same HEAD commit? -> merged by definition
+	if sourceHead.Equals(targetHead) {
+		return true, nil
+	}
+
+	// https://stackoverflow.com/a/40011122
+	// git merge-base <commit-hash-step1> <commit-hash-step2>
+	command := script.LocalCommandFrom("git merge-base")
+	command.AddAll(sourceHead.GetHash(), targetHead.GetHash())
+
+	pr, err := repository.run(command)
+	if err != nil {
+		return false, fmt.Errorf("could not find merge-base between %s and %s: %w", sourceHead.GetHash(), targetHead.GetHash(), err)
+	}
+	mergeBase := strings.TrimSpace(pr.Output())
+	return mergeBase == sourceHead.GetHash(), nil
+}
@@ -0,0 +1,215 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// BranchInfo contains the metadata of a branch as listed by git for-each-ref.
+type BranchInfo struct {
+	// Name without refs/heads/ or refs/remotes/<remote>/
+	Name     string
+	RefName  string
+	HeadHash string
+	// Upstream is the full ref name of the configured upstream, "" if none
+	Upstream string
+
+	// metadata of the HEAD commit
+	CommitterDate time.Time
+	AuthorName    string
+	AuthorEmail   string
+	Subject       string
+
+	// raw %(upstream:track)
+	upstreamTrack string
+}
+
+// BranchSort defines the order of listed branches.
+type BranchSort int
+
+const (
+	SortByName BranchSort = iota
+	// SortByCommitterDate lists the branches with the oldest HEAD commit first
+	SortByCommitterDate
+)
+
+// BranchListOptions configures listing branches.
+type BranchListOptions struct {
+	Sort    BranchSort
+	Reverse bool
+	// Patterns are path.Match globs on the branch name, all branches are listed if empty
+	Patterns []string
+}
+
+func (o BranchListOptions) sortArg() (string, error) {
+	var key string
+	switch o.Sort {
+	case SortByName:
+		key = "refname"
+	case SortByCommitterDate:
+		key = "committerdate"
+	default:
+		return "", fmt.Errorf("invalid branch sort %d", o.Sort)
+	}
+	if o.Reverse {
+		key = "-" + key
+	}
+	return "--sort=" + key, nil
+}
+
+// https://git-scm.com/docs/git-for-each-ref#_field_names
+var branchInfoPlaceholders = []string{
+	"%(refname)", "%(objectname)", "%(upstream)", "%(upstream:track)",
+	"%(committerdate:unix)", "%(authorname)", "%(authoremail)", "%(contents:subject)",
+}
+
+// ListBranches lists the local branches with their metadata using a single git
+// command. The metadata is a snapshot, see LocalBranch.GetInfo.
+func (r *Repository) ListBranches(opts BranchListOptions) ([]*LocalBranch, error) {
+	infos, err := r.listBranchInfos("refs/heads/", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]*LocalBranch, 0, len(infos))
+	for _, info := range infos {
+		branch := newLocalBranch(r, info.Name)
+		branch.info = info
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// ListBranches lists the remote-tracking branches of the remote with their
+// metadata using a single git command. Other than GetBranches, the remote is not
+// contacted, so the result is as recent as the last fetch.
+func (r *Remote) ListBranches(opts BranchListOptions) ([]*RemoteBranch, error) {
+	infos, err := r.repository.listBranchInfos("refs/remotes/"+r.GetName()+"/", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]*RemoteBranch, 0, len(infos))
+	for _, info := range infos {
+		// symbolic ref to the default branch of the remote
+		if info.Name == "HEAD" {
+			continue
+		}
+		branch := newRemoteBranch(r.repository, r, info.Name)
+		branch.info = info
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// listBranchInfos lists the refs below prefix, their names are relative to prefix.
+func (r *Repository) listBranchInfos(prefix string, opts BranchListOptions) ([]*BranchInfo, error) {
+	for _, pattern := range opts.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", pattern, err)
+		}
+	}
+	sortArg, err := opts.sortArg()
+	if err != nil {
+		return nil, err
+	}
+
+	// https://git-scm.com/docs/git-for-each-ref
+	// git for-each-ref --sort=<key> --format=<format> <prefix>
+	command := script.LocalCommandFrom("git for-each-ref")
+	command.AddAll(sortArg, "--format="+strings.Join(branchInfoPlaceholders, "%00"), prefix)
+	pr, err := r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not list refs %s: %w", prefix, err)
+	}
+
+	infos := make([]*BranchInfo, 0)
+	for _, line := range strings.Split(pr.TrimmedOutput(), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\x00")
+		if len(fields) != len(branchInfoPlaceholders) {
+			return nil, fmt.Errorf("invalid line format in ref list: %s", line)
+		}
+
+		name := strings.TrimPrefix(fields[0], prefix)
+		if !matchesGlobs(name, opts.Patterns, nil) {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		info := BranchInfo{
+			Name:          name,
+			RefName:       fields[0],
+			HeadHash:      fields[1],
+			Upstream:      fields[2],
+			upstreamTrack: fields[3],
+			CommitterDate: time.Unix(timestamp, 0),
+			AuthorName:    fields[5],
+			AuthorEmail:   strings.TrimSuffix(strings.TrimPrefix(fields[6], "<"), ">"),
+			Subject:       fields[7],
+		}
+		infos = append(infos, &info)
+	}
+	return infos, nil
+}
+
+// loadBranchInfo returns the metadata of a single branch below prefix.
+func (r *Repository) loadBranchInfo(prefix, name string) (*BranchInfo, error) {
+	// branch names cannot contain glob characters, so this is an exact match
+	infos, err := r.listBranchInfos(prefix, BranchListOptions{
+		Patterns: []string{name},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrBranchNotFound, prefix+name)
+	}
+	return infos[0], nil
+}
+
+// GetInfo returns the metadata of the branch. Branches retrieved by ListBranches
+// already contain it, otherwise it is loaded on first access. It is cached
+// afterwards and not updated when the branch changes.
+func (b *LocalBranch) GetInfo() (*BranchInfo, error) {
+	if b.info != nil {
+		return b.info, nil
+	}
+	info, err := b.repository.loadBranchInfo("refs/heads/", b.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	// put to cache
+	b.info = info
+
+	return info, nil
+}
+
+// GetInfo returns the metadata of the branch. Branches retrieved by
+// Remote.ListBranches already contain it, otherwise it is loaded on first
+// access. It is cached afterwards and not updated when the branch changes.
+func (b *RemoteBranch) GetInfo() (*BranchInfo, error) {
+	if b.info != nil {
+		return b.info, nil
+	}
+	info, err := b.repository.loadBranchInfo("refs/remotes/"+b.remote.GetName()+"/", b.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	// put to cache
+	b.info = info
+
+	return info, nil
+}
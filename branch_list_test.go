@@ -0,0 +1,87 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListBranches(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	git("commit", "--quiet", "--allow-empty", "-m", "first")
+	git("branch", "feature/a")
+	git("commit", "--quiet", "--allow-empty", "-m", "second")
+	git("branch", "feature/b")
+	git("remote", "add", "origin", "https://example.com/repo.git")
+	git("update-ref", "refs/remotes/origin/main", "main")
+	git("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main")
+
+	executor := &recordingExecutor{binaries: make(chan string, 1000)}
+	r, err := OpenRepository(r.GetPath(), WithExecutor(executor))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	branches, err := r.ListBranches(BranchListOptions{Patterns: []string{"feature/*"}, Reverse: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := r.GetRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteBranches, err := remote.ListBranches(BranchListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the HEAD commits are taken from the listing
+	calls := len(executor.binaries)
+	names := make([]string, 0)
+	hashes := make([]string, 0)
+	for _, branch := range branches {
+		commit, err := branch.GetHeadCommit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, branch.GetFullName())
+		hashes = append(hashes, commit.GetHash())
+	}
+	for _, branch := range remoteBranches {
+		commit, err := branch.GetHeadCommit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, branch.GetFullName())
+		hashes = append(hashes, commit.GetHash())
+	}
+	if len(executor.binaries) != calls {
+		t.Errorf("expected no commands for the HEAD commits, got %d", len(executor.binaries)-calls)
+	}
+
+	expectedNames := []string{"feature/b", "feature/a", "origin/main"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("expected branches %v, got %v", expectedNames, names)
+	}
+	expectedHashes := []string{git("rev-parse", "feature/b"), git("rev-parse", "feature/a"), git("rev-parse", "main")}
+	if !reflect.DeepEqual(hashes, expectedHashes) {
+		t.Errorf("expected HEAD commits %v, got %v", expectedHashes, hashes)
+	}
+	if branches[0].info.Subject != "second" || branches[1].info.Subject != "first" {
+		t.Errorf("unexpected subjects %s, %s", branches[0].info.Subject, branches[1].info.Subject)
+	}
+
+	// branches without metadata resolve the HEAD commit
+	branch, err := r.GetBranch("feature/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "--quiet", "--allow-empty", "-m", "third")
+	git("branch", "--force", "feature/a", "main")
+	commit, err := branch.GetHeadCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.GetHash() != git("rev-parse", "main") {
+		t.Errorf("expected current HEAD commit, got %s", commit.GetHash())
+	}
+}
@@ -0,0 +1,207 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// CreateBranchOptions configures Repository.CreateBranch.
+type CreateBranchOptions struct {
+	// Track sets the start point as upstream, otherwise no upstream is configured
+	Track bool
+	// Force resets an existing branch to the start point
+	Force bool
+}
+
+// CheckoutOptions configures checking out a branch.
+type CheckoutOptions struct {
+	// Create creates the branch before checking it out
+	Create bool
+	// StartPoint of a created branch, HEAD if empty
+	StartPoint string
+	// Force discards local modifications
+	Force bool
+}
+
+// CreateBranch creates a local branch starting at startPoint (HEAD if empty)
+// without checking it out.
+func (r *Repository) CreateBranch(name, startPoint string, opts CreateBranchOptions) (*LocalBranch, error) {
+	err := validateRefArguments(name, startPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// git branch [--track|--no-track] [--force] -- <name> [<start-point>]
+	command := script.LocalCommandFrom("git branch")
+	if opts.Track {
+		command.Add("--track")
+	} else {
+		command.Add("--no-track")
+	}
+	if opts.Force {
+		command.Add("--force")
+	}
+	command.AddAll("--", name)
+	if startPoint != "" {
+		command.Add(startPoint)
+	}
+
+	_, err = r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not create local branch %s: %w", name, err)
+	}
+	return newLocalBranch(r, name), nil
+}
+
+// SwitchBranch checks out the local branch name, creating it if opts.Create is set.
+func (r *Repository) SwitchBranch(name string, opts CheckoutOptions) (*LocalBranch, error) {
+	err := validateRefArguments(name, opts.StartPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	supportsSwitch, err := r.checkGitVersion(">= 2.23")
+	if err != nil {
+		return nil, err
+	}
+
+	var command *script.LocalCommand
+	if supportsSwitch {
+		// git switch --no-guess [--force] [--create] <name> [<start-point>]
+		command = script.LocalCommandFrom("git switch --no-guess")
+		if opts.Force {
+			command.Add("--force")
+		}
+		if opts.Create {
+			command.Add("--create")
+		}
+		command.Add(name)
+		if opts.Create && opts.StartPoint != "" {
+			command.Add(opts.StartPoint)
+		}
+	} else {
+		// Fallback
+		// git checkout [--force] [-b] <name> [<start-point>] --
+		command = script.LocalCommandFrom("git checkout")
+		if opts.Force {
+			command.Add("--force")
+		}
+		if opts.Create {
+			command.Add("-b")
+		}
+		command.Add(name)
+		if opts.Create && opts.StartPoint != "" {
+			command.Add(opts.StartPoint)
+		}
+		command.Add("--")
+	}
+
+	_, err = r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not switch to local branch %s: %w", name, err)
+	}
+	return newLocalBranch(r, name), nil
+}
+
+// Checkout checks out the branch.
+func (b *LocalBranch) Checkout(opts CheckoutOptions) error {
+	_, err := b.repository.SwitchBranch(b.GetName(), opts)
+	return err
+}
+
+// Switch is an alias of Checkout named after git switch.
+func (b *LocalBranch) Switch(opts CheckoutOptions) error {
+	return b.Checkout(opts)
+}
+
+func (b *LocalBranch) renameInternal(newName string, force bool) error {
+	err := validateRefArguments(newName)
+	if err != nil {
+		return err
+	}
+
+	// git branch --move [--force] -- <old> <new>
+	command := script.LocalCommandFrom("git branch --move")
+	if force {
+		command.Add("--force")
+	}
+	command.AddAll("--", b.GetName(), newName)
+
+	_, err = b.repository.run(command)
+	if err != nil {
+		return fmt.Errorf("could not rename local branch %s to %s: %w", b.GetName(), newName, err)
+	}
+
+	// keep caches consistent
+	if b.repository.mainLocalBranchName == b.name {
+		b.repository.mainLocalBranchName = ""
+	}
+	b.name = newName
+	b.info = nil
+	return nil
+}
+
+// Rename renames the branch, failing if newName already exists.
+func (b *LocalBranch) Rename(newName string) error {
+	return b.renameInternal(newName, false)
+}
+
+// ForceRename renames the branch, replacing an existing branch newName.
+func (b *LocalBranch) ForceRename(newName string) error {
+	return b.renameInternal(newName, true)
+}
+
+// SetUpstream configures upstream as the upstream branch of b, it may be a
+// remote or a local branch.
+func (b *LocalBranch) SetUpstream(upstream Branch) error {
+	var refName string
+	switch u := upstream.(type) {
+	case *LocalBranch:
+		refName = "refs/heads/" + u.GetName()
+	case *RemoteBranch:
+		refName = "refs/remotes/" + u.GetFullName()
+	default:
+		return fmt.Errorf("unsupported upstream branch type %T", upstream)
+	}
+
+	// git branch --set-upstream-to=<upstream> -- <name>
+	command := script.LocalCommandFrom("git branch")
+	command.AddAll("--set-upstream-to="+refName, "--", b.GetName())
+
+	_, err := b.repository.run(command)
+	if err != nil {
+		return fmt.Errorf("could not set upstream of %s to %s: %w", b.GetName(), upstream.GetFullName(), err)
+	}
+	b.info = nil
+	return nil
+}
+
+// UnsetUpstream removes the upstream configuration of the branch.
+func (b *LocalBranch) UnsetUpstream() error {
+	// git branch --unset-upstream -- <name>
+	command := script.LocalCommandFrom("git branch --unset-upstream --")
+	command.Add(b.GetName())
+
+	_, err := b.repository.run(command)
+	if err != nil {
+		return fmt.Errorf("could not unset upstream of %s: %w", b.GetName(), err)
+	}
+	b.info = nil
+	return nil
+}
+
+// validateRefArguments makes sure a user supplied name and optional revisions
+// cannot be interpreted as options.
+func validateRefArguments(name string, revisions ...string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidRefName)
+	}
+	for _, value := range append([]string{name}, revisions...) {
+		if strings.HasPrefix(value, "-") {
+			return fmt.Errorf("%w: %s", ErrInvalidRefName, value)
+		}
+	}
+	return nil
+}
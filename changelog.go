@@ -0,0 +1,312 @@
+package git
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ChangelogOptions configures Repository.GenerateChangelog.
+type ChangelogOptions struct {
+	// From is excluded, e.g. the last tag, the changelog covers the whole
+	// history of To if empty
+	From string
+	// To is included, HEAD if empty
+	To string
+	// Title of the changelog, e.g. the version to be released, To if empty
+	Title string
+
+	// Groups define the sections of the changelog in order, DefaultChangelogGroups
+	// is used if empty
+	Groups []ChangelogGroup
+	// OtherTitle is the title of the section for commits not matching any
+	// group, "Other Changes" if empty
+	OtherTitle string
+	// OmitOther leaves out commits not matching any group
+	OmitOther bool
+
+	// FirstParent lists the merge commits only for merged branches, so that
+	// every merge request is a single entry. Merge commits are skipped otherwise.
+	FirstParent bool
+	// ReferencePattern finds merge request references in commit messages, its
+	// first group is the ID. DefaultReferencePattern is used if nil.
+	ReferencePattern *regexp.Regexp
+	// ReferenceURL is a format string for the link of a reference, e.g.
+	// "https://github.com/org/repo/pull/%s" with %s replaced by the number.
+	// References are not linked if empty, references to other projects like
+	// "group/project!123" are never linked.
+	ReferenceURL string
+}
+
+// ChangelogGroup assigns commits to a changelog section either by their
+// Conventional Commits type or by a prefix of their subject.
+type ChangelogGroup struct {
+	Title string
+	// Types of Conventional Commits, e.g. "feat"
+	Types []string
+	// Prefixes of commit subjects, e.g. "[FEATURE]", they are removed from the entries
+	Prefixes []string
+}
+
+// DefaultChangelogGroups groups commits by the common Conventional Commits types.
+var DefaultChangelogGroups = []ChangelogGroup{
+	{Title: "Features", Types: []string{"feat"}},
+	{Title: "Bug Fixes", Types: []string{"fix"}},
+	{Title: "Performance Improvements", Types: []string{"perf"}},
+	{Title: "Reverts", Types: []string{"revert"}},
+}
+
+// DefaultReferencePattern matches GitHub pull requests like "#123", GitLab merge
+// requests like "!123" and "See merge request group/project!123".
+var DefaultReferencePattern = regexp.MustCompile(`(?:^|[\s(\[])((?:[\w.-]+/[\w./-]+)?[#!][0-9]+)\b`)
+
+// Changelog lists the commits between two revisions in sections.
+type Changelog struct {
+	Title    string              `json:"title"`
+	From     string              `json:"from,omitempty"`
+	To       string              `json:"to"`
+	Sections []*ChangelogSection `json:"sections"`
+	// Breaking contains the entries of all sections introducing breaking changes
+	Breaking []*ChangelogEntry `json:"breaking"`
+}
+
+type ChangelogSection struct {
+	Title   string            `json:"title"`
+	Entries []*ChangelogEntry `json:"entries"`
+}
+
+// ChangelogEntry is a single commit in the changelog.
+type ChangelogEntry struct {
+	Hash      string `json:"hash"`
+	ShortHash string `json:"shortHash"`
+	// Type and Scope are only set for Conventional Commits
+	Type  string `json:"type,omitempty"`
+	Scope string `json:"scope,omitempty"`
+	// Description is the subject without type, scope or group prefix
+	Description string `json:"description"`
+	Body        string `json:"body,omitempty"`
+	Breaking    bool   `json:"breaking"`
+	// BreakingNote is the value of the BREAKING CHANGE footer
+	BreakingNote string               `json:"breakingNote,omitempty"`
+	Author       string               `json:"author"`
+	Date         time.Time            `json:"date"`
+	References   []ChangelogReference `json:"references"`
+
+	Commit *Commit `json:"-"`
+}
+
+// ChangelogReference is a merge request referenced by a commit.
+type ChangelogReference struct {
+	// ID as found in the message, e.g. "#123"
+	ID string `json:"id"`
+	// URL is empty if no ChangelogOptions.ReferenceURL is set or the reference
+	// points to another project
+	URL string `json:"url,omitempty"`
+}
+
+// DefaultChangelogTemplate renders a changelog as Markdown.
+const DefaultChangelogTemplate = `{{ define "entry" -}}
+- {{ if .Scope }}**{{ .Scope }}:** {{ end }}{{ .Description }} ({{ .ShortHash }})
+{{- range .References }} {{ if .URL }}[{{ .ID }}]({{ .URL }}){{ else }}{{ .ID }}{{ end }}{{ end }}
+{{ end -}}
+## {{ .Title }}
+{{ if .Breaking }}
+### Breaking Changes
+
+{{ range .Breaking }}{{ template "entry" . }}{{ if .BreakingNote }}  {{ .BreakingNote }}
+{{ end }}{{ end }}{{ end }}{{ range .Sections }}
+### {{ .Title }}
+
+{{ range .Entries }}{{ template "entry" . }}{{ end }}{{ end }}`
+
+var defaultChangelogTemplate = template.Must(template.New("changelog").Parse(DefaultChangelogTemplate))
+
+// GenerateChangelog creates a changelog of the commits reachable from opts.To
+// but not from opts.From.
+func (r *Repository) GenerateChangelog(opts ChangelogOptions) (*Changelog, error) {
+	to := opts.To
+	if to == "" {
+		to = "HEAD"
+	}
+	revisions := []string{to}
+	if opts.From != "" {
+		revisions = append(revisions, "^"+opts.From)
+	}
+	title := opts.Title
+	if title == "" {
+		title = to
+	}
+	groups := opts.Groups
+	if len(groups) == 0 {
+		groups = DefaultChangelogGroups
+	}
+	otherTitle := opts.OtherTitle
+	if otherTitle == "" {
+		otherTitle = "Other Changes"
+	}
+
+	it, err := r.Log(LogOptions{
+		Revisions:   revisions,
+		FirstParent: opts.FirstParent,
+		NoMerges:    !opts.FirstParent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	commits, err := it.All()
+	if err != nil {
+		return nil, fmt.Errorf("could not list commits for changelog: %w", err)
+	}
+
+	changelog := Changelog{
+		Title:    title,
+		From:     opts.From,
+		To:       to,
+		Sections: []*ChangelogSection{},
+		Breaking: []*ChangelogEntry{},
+	}
+	sections := make([]*ChangelogSection, len(groups)+1)
+	for i, group := range groups {
+		sections[i] = &ChangelogSection{Title: group.Title, Entries: []*ChangelogEntry{}}
+	}
+	other := &ChangelogSection{Title: otherTitle, Entries: []*ChangelogEntry{}}
+	sections[len(groups)] = other
+
+	for _, commit := range commits {
+		entry, groupIndex, err := newChangelogEntry(commit, groups, opts)
+		if err != nil {
+			return nil, err
+		}
+		if entry.Breaking {
+			changelog.Breaking = append(changelog.Breaking, entry)
+		}
+		if groupIndex < 0 {
+			if opts.OmitOther {
+				continue
+			}
+			other.Entries = append(other.Entries, entry)
+			continue
+		}
+		sections[groupIndex].Entries = append(sections[groupIndex].Entries, entry)
+	}
+
+	for _, section := range sections {
+		if len(section.Entries) > 0 {
+			changelog.Sections = append(changelog.Sections, section)
+		}
+	}
+	return &changelog, nil
+}
+
+// newChangelogEntry creates the entry of commit and returns the index of the
+// matching group, -1 if none matches.
+func newChangelogEntry(commit *Commit, groups []ChangelogGroup, opts ChangelogOptions) (*ChangelogEntry, int, error) {
+	info, err := commit.GetInfo()
+	if err != nil {
+		return nil, -1, err
+	}
+	entry := ChangelogEntry{
+		Hash:        info.Hash,
+		ShortHash:   info.ShortHash,
+		Description: info.Subject,
+		Body:        info.Body,
+		Author:      info.AuthorName,
+		Date:        info.AuthorDate,
+		References:  findChangelogReferences(info.RawMessage, opts),
+		Commit:      commit,
+	}
+
+	conventional, err := ParseConventionalCommit(info.RawMessage)
+	switch {
+	case err == nil:
+		entry.Type = conventional.Type
+		entry.Scope = conventional.Scope
+		entry.Description = conventional.Description
+		entry.Body = conventional.Body
+		entry.Breaking = conventional.Breaking
+		for _, footer := range conventional.Footers {
+			if footer.IsBreakingChange() {
+				entry.BreakingNote = footer.Value
+			}
+		}
+	case !errors.Is(err, ErrNotConventional):
+		return nil, -1, err
+	}
+
+	for i, group := range groups {
+		if entry.Type != "" && containsString(group.Types, entry.Type) {
+			return &entry, i, nil
+		}
+		for _, prefix := range group.Prefixes {
+			if strings.HasPrefix(info.Subject, prefix) {
+				entry.Description = strings.TrimSpace(strings.TrimPrefix(info.Subject, prefix))
+				return &entry, i, nil
+			}
+		}
+	}
+	return &entry, -1, nil
+}
+
+// findChangelogReferences returns the distinct merge request references in message.
+func findChangelogReferences(message string, opts ChangelogOptions) []ChangelogReference {
+	pattern := opts.ReferencePattern
+	if pattern == nil {
+		pattern = DefaultReferencePattern
+	}
+
+	references := make([]ChangelogReference, 0)
+	seen := make(map[string]bool)
+	for _, matches := range pattern.FindAllStringSubmatch(message, -1) {
+		if len(matches) < 2 || seen[matches[1]] {
+			continue
+		}
+		seen[matches[1]] = true
+
+		reference := ChangelogReference{
+			ID: matches[1],
+		}
+		// references to other projects like group/project!123 would be linked
+		// to the wrong project
+		separator := strings.LastIndexAny(matches[1], "#!")
+		if opts.ReferenceURL != "" && separator <= 0 {
+			reference.URL = fmt.Sprintf(opts.ReferenceURL, matches[1][separator+1:])
+		}
+		references = append(references, reference)
+	}
+	return references
+}
+
+// Render executes tmpl with the changelog as data.
+func (c *Changelog) Render(w io.Writer, tmpl *template.Template) error {
+	return tmpl.Execute(w, c)
+}
+
+// Markdown renders the changelog using DefaultChangelogTemplate.
+func (c *Changelog) Markdown() (string, error) {
+	var buf bytes.Buffer
+	err := c.Render(&buf, defaultChangelogTemplate)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// JSON encodes the changelog, commits are represented by their metadata.
+func (c *Changelog) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package git
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestFindChangelogReferences(t *testing.T) {
+	opts := ChangelogOptions{
+		ReferenceURL: "https://example.com/project/merge_requests/%s",
+	}
+	tests := []struct {
+		message  string
+		opts     ChangelogOptions
+		expected []ChangelogReference
+	}{
+		{
+			message: "fix: crash (#12)",
+			opts:    opts,
+			expected: []ChangelogReference{
+				{ID: "#12", URL: "https://example.com/project/merge_requests/12"},
+			},
+		},
+		{
+			message: "Merge branch 'x' into 'main'\n\nSee merge request group/other!42\nRelated to !7 and !7",
+			opts:    opts,
+			expected: []ChangelogReference{
+				{ID: "group/other!42"},
+				{ID: "!7", URL: "https://example.com/project/merge_requests/7"},
+			},
+		},
+		{
+			message:  "feat: no references, issue#3 is not one",
+			opts:     opts,
+			expected: []ChangelogReference{},
+		},
+		{
+			message: "fix: crash (#12)",
+			opts:    ChangelogOptions{},
+			expected: []ChangelogReference{
+				{ID: "#12"},
+			},
+		},
+		{
+			message: "fix: crash\n\nRefs: PROJ-5",
+			opts: ChangelogOptions{
+				ReferencePattern: regexp.MustCompile(`\b(PROJ-[0-9]+)\b`),
+				ReferenceURL:     "https://tracker.example.com/browse/%s",
+			},
+			expected: []ChangelogReference{
+				{ID: "PROJ-5", URL: "https://tracker.example.com/browse/PROJ-5"},
+			},
+		},
+	}
+	for _, test := range tests {
+		actual := findChangelogReferences(test.message, test.opts)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%q: expected %+v, got %+v", test.message, test.expected, actual)
+		}
+	}
+}
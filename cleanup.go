@@ -0,0 +1,278 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// CleanupReason explains why a branch is a cleanup candidate.
+type CleanupReason string
+
+const (
+	CleanupReasonMerged       CleanupReason = "merged"
+	CleanupReasonUpstreamGone CleanupReason = "upstream gone"
+	CleanupReasonStale        CleanupReason = "stale"
+)
+
+// CleanupOptions configures Repository.PlanCleanup. A branch becomes a candidate
+// if it matches the globs and any of the enabled criteria. The main branches, the
+// default branches of the remotes, the current branch, its upstream and the merge
+// target are never candidates.
+type CleanupOptions struct {
+	// Target is the branch merges are checked against, the main branch if nil
+	Target Branch
+
+	// SkipLocal excludes local branches
+	SkipLocal bool
+	// Remotes whose branches are considered, based on the local remote-tracking
+	// branches (consider fetching with --prune first)
+	Remotes []*Remote
+
+	// Merged selects branches merged to Target
+	Merged bool
+	// DetectSquashMerges also treats rebased and squashed branches as merged
+	DetectSquashMerges bool
+	// UpstreamGone selects local branches whose configured upstream was deleted
+	UpstreamGone bool
+	// OlderThan selects branches whose HEAD commit is older if > 0
+	OlderThan time.Duration
+
+	// Include and Exclude are path.Match globs on the branch name (without the
+	// remote), Include matches all branches if empty
+	Include []string
+	Exclude []string
+
+	// Force deletes local branches even if git considers them unmerged
+	Force bool
+}
+
+// CleanupCandidate is a branch planned to be deleted.
+type CleanupCandidate struct {
+	Branch  Branch
+	Reasons []CleanupReason
+}
+
+// CleanupResult is the outcome of deleting a single candidate.
+type CleanupResult struct {
+	Branch Branch
+	Err    error
+}
+
+// CleanupPlan lists the branches to be deleted, it is not applied before
+// Execute is called.
+type CleanupPlan struct {
+	Candidates []*CleanupCandidate
+
+	force bool
+}
+
+// PlanCleanup computes the branches to delete according to opts.
+func (r *Repository) PlanCleanup(opts CleanupOptions) (*CleanupPlan, error) {
+	plan := CleanupPlan{
+		Candidates: []*CleanupCandidate{},
+		force:      opts.Force,
+	}
+
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %s: %w", pattern, err)
+		}
+	}
+
+	protected, err := r.getProtectedBranchNames(opts.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	target := opts.Target
+	if target == nil && opts.Merged {
+		target, err = r.GetMainBranch()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	listOptions := BranchListOptions{
+		Patterns: opts.Include,
+	}
+	var sources []Branch
+	var infos []*BranchInfo
+	if !opts.SkipLocal {
+		localBranches, err := r.ListBranches(listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range localBranches {
+			sources = append(sources, branch)
+			infos = append(infos, branch.info)
+		}
+	}
+	for _, remote := range opts.Remotes {
+		remoteBranches, err := remote.ListBranches(listOptions)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range remoteBranches {
+			sources = append(sources, branch)
+			infos = append(infos, branch.info)
+		}
+	}
+
+	for i, branch := range sources {
+		info := infos[i]
+		if protected[branch.GetFullName()] || !matchesGlobs(branch.GetName(), nil, opts.Exclude) {
+			continue
+		}
+
+		reasons := make([]CleanupReason, 0, 3)
+		if opts.Merged {
+			merged, err := isCleanupMerged(r, branch, target, opts.DetectSquashMerges)
+			if err != nil {
+				return nil, err
+			}
+			if merged {
+				reasons = append(reasons, CleanupReasonMerged)
+			}
+		}
+		if opts.UpstreamGone && info.Upstream != "" {
+			_, _, gone, err := parseUpstreamTrack(info.upstreamTrack)
+			if err != nil {
+				return nil, err
+			}
+			if gone {
+				reasons = append(reasons, CleanupReasonUpstreamGone)
+			}
+		}
+		if opts.OlderThan > 0 && time.Since(info.CommitterDate) > opts.OlderThan {
+			reasons = append(reasons, CleanupReasonStale)
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+		plan.Candidates = append(plan.Candidates, &CleanupCandidate{
+			Branch:  branch,
+			Reasons: reasons,
+		})
+	}
+
+	return &plan, nil
+}
+
+// Execute deletes all candidates of the plan. It continues on errors, which are
+// reported per branch.
+func (p *CleanupPlan) Execute() []CleanupResult {
+	results := make([]CleanupResult, 0, len(p.Candidates))
+	for _, candidate := range p.Candidates {
+		var err error
+		if localBranch, ok := candidate.Branch.(*LocalBranch); ok && p.force {
+			err = localBranch.ForceDelete()
+		} else {
+			err = candidate.Branch.Delete()
+		}
+		results = append(results, CleanupResult{
+			Branch: candidate.Branch,
+			Err:    err,
+		})
+	}
+	return results
+}
+
+func (c *CleanupCandidate) String() string {
+	reasons := make([]string, 0, len(c.Reasons))
+	for _, reason := range c.Reasons {
+		reasons = append(reasons, string(reason))
+	}
+	return fmt.Sprintf("%s (%s)", c.Branch, strings.Join(reasons, ", "))
+}
+
+// getProtectedBranchNames returns the full names of the branches that must not
+// be cleaned up.
+func (r *Repository) getProtectedBranchNames(target Branch) (map[string]bool, error) {
+	protected := make(map[string]bool)
+	if target != nil {
+		protected[target.GetFullName()] = true
+	}
+
+	main, err := r.GetMainBranch()
+	if err != nil && !errors.Is(err, ErrNoMainBranch) {
+		return nil, err
+	}
+	if err == nil {
+		protected[main.GetFullName()] = true
+	}
+
+	current, err := r.GetCurrentBranch()
+	if err != nil && !errors.Is(err, ErrDetachedHead) {
+		return nil, err
+	}
+	if err == nil {
+		protected[current.GetFullName()] = true
+
+		upstream, err := current.GetUpstream()
+		if err != nil && !errors.Is(err, ErrNoUpstream) {
+			return nil, err
+		}
+		if err == nil {
+			protected[upstream.GetFullName()] = true
+		}
+	}
+
+	remotes, err := r.GetRemotes()
+	if err != nil {
+		return nil, err
+	}
+	for _, remote := range remotes {
+		remoteMain, err := remote.GetMainBranch()
+		if err != nil && !errors.Is(err, ErrNoMainBranch) {
+			return nil, err
+		}
+		if err == nil {
+			protected[remoteMain.GetFullName()] = true
+		}
+
+		// the default branch of the remote is not necessarily named like a main branch
+		defaultBranchName, err := remote.getDefaultBranchName()
+		if err != nil {
+			return nil, err
+		}
+		if defaultBranchName != "" {
+			protected[remote.GetName()+"/"+defaultBranchName] = true
+		}
+	}
+
+	return protected, nil
+}
+
+func isCleanupMerged(repository *Repository, branch, target Branch, detectSquashMerges bool) (bool, error) {
+	if !detectSquashMerges {
+		return isMergedTo(repository, branch, target)
+	}
+	report, err := isEffectivelyMergedTo(repository, branch, target)
+	if err != nil {
+		return false, err
+	}
+	return report.Merged, nil
+}
+
+// matchesGlobs checks name against path.Match patterns. It is matched if any
+// include pattern (or no include pattern at all) and no exclude pattern match.
+func matchesGlobs(name string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
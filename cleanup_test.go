@@ -0,0 +1,182 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCleanup(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	origin := t.TempDir()
+	git("init", "--quiet", "--bare", origin)
+	git("remote", "add", "origin", origin)
+
+	git("commit", "--quiet", "--allow-empty", "-m", "init")
+	git("checkout", "--quiet", "-b", "feature/merged")
+	git("commit", "--quiet", "--allow-empty", "-m", "merged work")
+	git("checkout", "--quiet", "main")
+	git("merge", "--quiet", "--no-ff", "-m", "merge", "feature/merged")
+	git("branch", "feature/keep")
+
+	// unmerged with an old commit
+	stale, err := r.CommitTree(CommitTreeOptions{
+		Tree:          git("rev-parse", "main^{tree}"),
+		Parents:       []string{git("rev-parse", "main")},
+		Message:       "old work",
+		CommitterDate: time.Now().Add(-48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	git("branch", "feature/open", stale.GetHash())
+
+	// unmerged with a deleted upstream
+	git("checkout", "--quiet", "-b", "gone")
+	git("commit", "--quiet", "--allow-empty", "-m", "gone work")
+	git("push", "--quiet", "--set-upstream", "origin", "gone")
+	git("push", "--quiet", "origin", "--delete", "gone")
+
+	// the default branch of the remote is merged, but not named like a main branch
+	git("push", "--quiet", "origin", "main", "feature/merged", "main:develop")
+	git("remote", "set-head", "origin", "develop")
+
+	// current branch with merged upstream
+	git("checkout", "--quiet", "-b", "current", "main")
+	git("push", "--quiet", "--set-upstream", "origin", "current")
+
+	remote, err := r.GetRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	openBranch, err := r.GetBranch("feature/open")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     CleanupOptions
+		expected []string
+	}{
+		{
+			name: "all criteria",
+			opts: CleanupOptions{
+				Remotes:      []*Remote{remote},
+				Merged:       true,
+				UpstreamGone: true,
+				OlderThan:    24 * time.Hour,
+				Exclude:      []string{"feature/keep"},
+			},
+			expected: []string{
+				"feature/merged [merged]",
+				"feature/open [stale]",
+				"gone [upstream gone]",
+				"origin/feature/merged [merged]",
+			},
+		},
+		{
+			name: "include",
+			opts: CleanupOptions{
+				Remotes: []*Remote{remote},
+				Merged:  true,
+				Include: []string{"feature/*"},
+			},
+			expected: []string{
+				"feature/keep [merged]",
+				"feature/merged [merged]",
+				"origin/feature/merged [merged]",
+			},
+		},
+		{
+			name: "target",
+			opts: CleanupOptions{
+				Target:    openBranch,
+				SkipLocal: true,
+				Remotes:   []*Remote{remote},
+				Merged:    true,
+				OlderThan: 24 * time.Hour,
+			},
+			expected: []string{
+				"origin/feature/merged [merged]",
+			},
+		},
+		{
+			name: "protected target",
+			opts: CleanupOptions{
+				Target:    openBranch,
+				OlderThan: 24 * time.Hour,
+			},
+			expected: []string{},
+		},
+	}
+	for _, test := range tests {
+		plan, err := r.PlanCleanup(test.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		actual := make([]string, 0, len(plan.Candidates))
+		for _, candidate := range plan.Candidates {
+			actual = append(actual, fmt.Sprintf("%s %v", candidate.Branch.GetFullName(), candidate.Reasons))
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s: expected candidates %v, got %v", test.name, test.expected, actual)
+		}
+	}
+
+	_, err = r.PlanCleanup(CleanupOptions{Include: []string{"["}})
+	if err == nil {
+		t.Error("expected error for invalid glob")
+	}
+
+	// unmerged local branches are not deleted without Force
+	plan, err := r.PlanCleanup(tests[0].opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := plan.Execute()
+	if len(results) != len(plan.Candidates) {
+		t.Fatalf("expected %d results, got %d", len(plan.Candidates), len(results))
+	}
+	for _, result := range results {
+		name := result.Branch.GetFullName()
+		switch name {
+		case "feature/open", "gone":
+			if !errors.Is(result.Err, ErrBranchNotMerged) {
+				t.Errorf("%s: expected ErrBranchNotMerged, got %v", name, result.Err)
+			}
+		default:
+			if result.Err != nil {
+				t.Errorf("%s: unexpected error %v", name, result.Err)
+			}
+		}
+	}
+	remaining := git("for-each-ref", "--format=%(refname:short)")
+	expectedRefs := "current\nfeature/keep\nfeature/open\ngone\nmain\norigin/HEAD\norigin/current\norigin/develop\norigin/main"
+	if remaining != expectedRefs {
+		t.Errorf("expected refs\n%s\ngot\n%s", expectedRefs, remaining)
+	}
+	if git("ls-remote", "--heads", "origin", "feature/merged") != "" {
+		t.Error("expected feature/merged to be deleted on the remote")
+	}
+
+	forced := tests[0].opts
+	forced.Force = true
+	plan, err = r.PlanCleanup(forced)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Candidates) != 2 {
+		t.Errorf("expected the unmerged branches to be left, got %v", plan.Candidates)
+	}
+	for _, result := range plan.Execute() {
+		if result.Err != nil {
+			t.Errorf("%s: unexpected error with Force %v", result.Branch.GetFullName(), result.Err)
+		}
+	}
+	if git("for-each-ref", "--format=%(refname:short)", "refs/heads/gone", "refs/heads/feature/open") != "" {
+		t.Error("expected unmerged branches to be deleted with Force")
+	}
+}
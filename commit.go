@@ -13,11 +13,24 @@ type Commit struct {
 	hash string
 
 	repository *Repository
+
+	// cached values
+	info *CommitInfo
+}
+
+// newCommitFromInfo creates a Commit with already loaded metadata.
+func newCommitFromInfo(repository *Repository, info *CommitInfo) (*Commit, error) {
+	commit, err := newCommit(repository, info.Hash)
+	if err != nil {
+		return nil, err
+	}
+	commit.info = info
+	return commit, nil
 }
 
 func newCommit(repository *Repository, hash string) (*Commit, error) {
 	if !IsValidCommitHash(hash) {
-		return nil, fmt.Errorf("invalid hash upon commit creation: %s", hash)
+		return nil, fmt.Errorf("%w upon commit creation: %s", ErrInvalidCommitHash, hash)
 	}
 
 	commit := Commit{
@@ -32,59 +45,146 @@ func (c *Commit) GetHash() string {
 }
 
 func (c *Commit) GetFullHash() (string, error) {
-	return c.getCommitValue("%H")
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.Hash, nil
 }
 
 func (c *Commit) GetShortHash() (string, error) {
-	return c.getCommitValue("%h")
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.ShortHash, nil
 }
 
 func (c *Commit) GetPatchId() (string, error) {
-	lc := &script.LocalCommand{}
-	lc.AddAll("sh", "-c", fmt.Sprintf("git show %s | git patch-id", c.GetHash()))
-	pr, err := c.repository.Execute(lc)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not get patch-id for commit %s", c.GetHash())
-	}
+	// git show <hash> | git patch-id
+	command := script.LocalCommandFrom("git show")
+	command.AddAll(c.GetHash(), "--")
+	output, err := c.repository.patchIds(command)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("could not get patch-id for commit %s: %w", c.GetHash(), err)
 	}
 
 	r := regexp.MustCompile(`^[^ ]+`)
-	return r.FindString(pr.Output()), nil
+	return r.FindString(output), nil
 }
 
 func (c *Commit) GetMessage() (string, error) {
-	return c.getCommitValue("%s")
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.Subject, nil
 }
 
 func (c *Commit) GetBody() (string, error) {
-	return c.getCommitValue("%b")
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.Body, nil
 }
 
 func (c *Commit) GetAuthorName() (string, error) {
-	return c.getCommitValue("%aN")
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.AuthorName, nil
 }
 
 func (c *Commit) GetAuthorEmail() (string, error) {
-	return c.getCommitValue("%aE")
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.AuthorEmail, nil
 }
 
 func (c *Commit) GetAuthorDate() (time.Time, error) {
-	dateString, err := c.getCommitValue("%aD")
+	info, err := c.GetInfo()
 	if err != nil {
 		return time.Time{}, err
 	}
-	const format = "Mon, 2 Jan 2006 15:04:05 -0700"
-	d, err := time.Parse(format, dateString)
+	return info.AuthorDate, nil
+}
+
+func (c *Commit) GetAuthorDateRelative() (string, error) {
+	return c.getCommitValue("%ar")
+}
+
+func (c *Commit) GetCommitterName() (string, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.CommitterName, nil
+}
+
+func (c *Commit) GetCommitterEmail() (string, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.CommitterEmail, nil
+}
+
+func (c *Commit) GetCommitterDate() (time.Time, error) {
+	info, err := c.GetInfo()
 	if err != nil {
 		return time.Time{}, err
 	}
-	return d, nil
+	return info.CommitterDate, nil
 }
 
-func (c *Commit) GetAuthorDateRelative() (string, error) {
-	return c.getCommitValue("%ar")
+// GetRawMessage returns the unmodified commit message including trailers like
+// Signed-off-by.
+func (c *Commit) GetRawMessage() (string, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.RawMessage, nil
+}
+
+func (c *Commit) GetTreeHash() (string, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return "", err
+	}
+	return info.TreeHash, nil
+}
+
+// GetParents returns the parent commits, the first parent being the commit that
+// was checked out when merging. Root commits have no parents.
+func (c *Commit) GetParents() ([]*Commit, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make([]*Commit, 0, len(info.ParentHashes))
+	for _, hash := range info.ParentHashes {
+		parent, err := newCommit(c.repository, hash)
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, parent)
+	}
+	return parents, nil
+}
+
+// IsMergeCommit returns true iff the commit has more than one parent.
+func (c *Commit) IsMergeCommit() (bool, error) {
+	info, err := c.GetInfo()
+	if err != nil {
+		return false, err
+	}
+	return len(info.ParentHashes) > 1, nil
 }
 
 // GetLocalBranches returns a list of all local branches containing this commit
@@ -94,16 +194,13 @@ func (c *Commit) GetLocalBranches() ([]*LocalBranch, error) {
 	command := script.LocalCommandFrom("git branch --contains")
 	hash, err := c.GetFullHash()
 	if err != nil {
-		return localBranches, nil
+		return localBranches, err
 	}
 	command.Add(hash)
 
-	pr, err := c.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not list local branches containing commit %s", hash)
-	}
+	pr, err := c.repository.run(command)
 	if err != nil {
-		return localBranches, err
+		return localBranches, fmt.Errorf("could not list local branches containing commit %s: %w", hash, err)
 	}
 
 	branchList, err := parseStarredBranchList(pr.Output())
@@ -125,16 +222,13 @@ func (c *Commit) GetRemoteBranches(remote *Remote) ([]*RemoteBranch, error) {
 	command := script.LocalCommandFrom("git branch --all --contains")
 	hash, err := c.GetFullHash()
 	if err != nil {
-		return remoteBranches, nil
+		return remoteBranches, err
 	}
 	command.Add(hash)
 
-	pr, err := c.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not list remote branches containing commit %s", hash)
-	}
+	pr, err := c.repository.run(command)
 	if err != nil {
-		return remoteBranches, err
+		return remoteBranches, fmt.Errorf("could not list remote branches containing commit %s: %w", hash, err)
 	}
 
 	branchList, err := parseStarredBranchList(pr.Output())
@@ -188,12 +282,9 @@ func (c *Commit) getCommitValue(param string) (string, error) {
 	command := script.LocalCommandFrom("git show")
 	command.AddAll(c.GetHash(), "--pretty=format:"+param, "--no-patch")
 
-	pr, err := c.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not get log data for commit %s", c.GetHash())
-	}
+	pr, err := c.repository.run(command)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("could not get log data for commit %s: %w", c.GetHash(), err)
 	}
 	return strings.TrimSpace(pr.Output()), nil
 }
@@ -0,0 +1,163 @@
+package git
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// SignMode defines whether a commit is signed.
+type SignMode int
+
+const (
+	// SignDefault signs according to the commit.gpgSign configuration
+	SignDefault SignMode = iota
+	SignEnabled
+	SignDisabled
+)
+
+// CommitOptions configures Repository.Commit.
+type CommitOptions struct {
+	// Message is required unless amending, where the previous message is kept
+	// if empty
+	Message string
+
+	// AuthorName, AuthorEmail and AuthorDate override the configured identity
+	// and the current time, they are passed via the environment
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	// CommitterName, CommitterEmail and CommitterDate override the configured
+	// identity and the current time, they are passed via the environment
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  time.Time
+
+	// All stages modified and deleted tracked files before committing
+	All        bool
+	AllowEmpty bool
+	// Amend replaces the current commit. Its author is kept unless an author
+	// option is set, then the author is reset to these values falling back to
+	// the configuration.
+	Amend bool
+	// SignOff adds a Signed-off-by trailer
+	SignOff bool
+
+	Sign SignMode
+	// SigningKey selects the key if Sign is SignEnabled, user.signingKey if empty
+	SigningKey string
+	// SigningFormat is "openpgp", "ssh" or "x509" overriding gpg.format for this
+	// commit only, the configuration is used if empty
+	SigningFormat string
+
+	// NoVerify skips the pre-commit and commit-msg hooks
+	NoVerify bool
+}
+
+// Commit records the staged changes as a new commit on the current branch and
+// returns it. The user configuration is not modified.
+func (r *Repository) Commit(opts CommitOptions) (*Commit, error) {
+	if opts.Message == "" && !opts.Amend {
+		return nil, fmt.Errorf("could not commit: empty commit message")
+	}
+
+	// git [-c gpg.format=<format>] commit [<options>] (--message=<message> | --no-edit)
+	command := script.LocalCommandFrom("git")
+	if opts.SigningFormat != "" {
+		command.AddAll("-c", "gpg.format="+opts.SigningFormat)
+	}
+	command.AddAll("commit", "--quiet")
+	if opts.All {
+		command.Add("--all")
+	}
+	if opts.AllowEmpty {
+		command.Add("--allow-empty")
+	}
+	authorEnv := identityEnv("AUTHOR", opts.AuthorName, opts.AuthorEmail, opts.AuthorDate)
+	if opts.Amend {
+		command.Add("--amend")
+		// the environment is ignored for the author of an amended commit otherwise
+		if len(authorEnv) > 0 {
+			command.Add("--reset-author")
+		}
+	}
+	if opts.SignOff {
+		command.Add("--signoff")
+	}
+	switch opts.Sign {
+	case SignDefault:
+	case SignEnabled:
+		if opts.SigningKey != "" {
+			command.Add("--gpg-sign=" + opts.SigningKey)
+		} else {
+			command.Add("--gpg-sign")
+		}
+	case SignDisabled:
+		command.Add("--no-gpg-sign")
+	default:
+		return nil, fmt.Errorf("invalid sign mode %d", opts.Sign)
+	}
+	if opts.NoVerify {
+		command.Add("--no-verify")
+	}
+	if opts.Message != "" {
+		command.Add("--message=" + opts.Message)
+	} else {
+		command.Add("--no-edit")
+	}
+
+	// git commit fails without changes, the reason is only given as
+	// human-readable text, so it is checked beforehand
+	if !opts.AllowEmpty && !opts.Amend {
+		changes, err := r.hasChangesToCommit(opts.All)
+		if err != nil {
+			return nil, fmt.Errorf("could not commit: %w", err)
+		}
+		if !changes {
+			return nil, fmt.Errorf("could not commit: %w", ErrNothingToCommit)
+		}
+	}
+
+	env := append(authorEnv, identityEnv("COMMITTER", opts.CommitterName, opts.CommitterEmail, opts.CommitterDate)...)
+
+	_, err := r.runInvocation(&Invocation{
+		Command: command,
+		Env:     env,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not commit: %w", err)
+	}
+	return r.GetCurrentCommit()
+}
+
+// hasChangesToCommit checks for staged changes, including modified tracked files
+// if all is set.
+func (r *Repository) hasChangesToCommit(all bool) (bool, error) {
+	head, err := r.resolveHeadCommit()
+	if err != nil {
+		return false, err
+	}
+
+	// git diff --quiet --cached
+	// git diff --quiet HEAD
+	// there are no tracked files to be modified on an unborn branch
+	command := script.LocalCommandFrom("git diff --quiet")
+	if all && head != nil {
+		command.AddAll("HEAD", "--")
+	} else {
+		command.AddAll("--cached", "--")
+	}
+	pr, err := r.Execute(command)
+	if err != nil {
+		return false, err
+	}
+	// exit code 1 means there are differences
+	if pr.ExitCode() == 1 {
+		return true, nil
+	}
+	if !pr.Successful() {
+		return false, newGitCommandError(command, pr)
+	}
+	return false, nil
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCommit(t *testing.T) {
+	r, git := newTestRepository(t, false)
+
+	// unborn branch with an untracked file only
+	writeFile(t, r, "a.txt", "a\n")
+	_, err := r.Commit(CommitOptions{Message: "nothing"})
+	if !errors.Is(err, ErrNothingToCommit) {
+		t.Fatalf("expected ErrNothingToCommit on unborn branch, got %v", err)
+	}
+
+	git("add", "a.txt")
+	date := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("", -5*3600))
+	commit, err := r.Commit(CommitOptions{
+		Message:     "first\n\nbody",
+		AuthorName:  "Bot",
+		AuthorEmail: "bot@example.com",
+		AuthorDate:  date,
+		SignOff:     true,
+		Sign:        SignDisabled,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := commit.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.AuthorName != "Bot" || info.AuthorEmail != "bot@example.com" || !info.AuthorDate.Equal(date) {
+		t.Errorf("unexpected author %s <%s> %s", info.AuthorName, info.AuthorEmail, info.AuthorDate)
+	}
+	if info.CommitterName != "Test Committer" {
+		t.Errorf("expected committer from environment, got %s", info.CommitterName)
+	}
+	if info.Subject != "first" || info.Body != "body\n\nSigned-off-by: Test Committer <committer@example.com>" {
+		t.Errorf("unexpected message %q", info.RawMessage)
+	}
+
+	// untracked files only
+	writeFile(t, r, "b.txt", "b\n")
+	_, err = r.Commit(CommitOptions{Message: "nothing"})
+	if !errors.Is(err, ErrNothingToCommit) {
+		t.Errorf("expected ErrNothingToCommit with untracked files, got %v", err)
+	}
+
+	// modified tracked files are only committed with All
+	writeFile(t, r, "a.txt", "a\na\n")
+	_, err = r.Commit(CommitOptions{Message: "nothing"})
+	if !errors.Is(err, ErrNothingToCommit) {
+		t.Errorf("expected ErrNothingToCommit with unstaged changes, got %v", err)
+	}
+	second, err := r.Commit(CommitOptions{Message: "second", All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if git("rev-parse", "HEAD") != second.GetHash() {
+		t.Errorf("expected HEAD to be %s", second.GetHash())
+	}
+
+	// amending keeps the author
+	amended, err := r.Commit(CommitOptions{Amend: true, Message: "second amended"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err = amended.GetInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Subject != "second amended" || info.AuthorName != "Test Author" || len(info.ParentHashes) != 1 || info.ParentHashes[0] != commit.GetHash() {
+		t.Errorf("unexpected amended commit %+v", info)
+	}
+
+	_, err = r.Commit(CommitOptions{Message: "empty", AllowEmpty: true})
+	if err != nil {
+		t.Errorf("expected empty commit to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// CommitInfo contains the metadata of a commit.
+type CommitInfo struct {
+	Hash         string
+	ShortHash    string
+	TreeHash     string
+	ParentHashes []string
+
+	AuthorName     string
+	AuthorEmail    string
+	AuthorDate     time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  time.Time
+
+	// Subject is the first paragraph of the message joined to a single line
+	Subject string
+	// Body is the message without the subject, trimmed
+	Body string
+	// RawMessage is the unmodified message including trailers
+	RawMessage string
+}
+
+// commitInfoFormat is the --format for git log and git show to be parsed by
+// parseCommitInfo. Fields are separated by NUL, in combination with -z every
+// commit is terminated by NUL too.
+var commitInfoFormat = "--format=" + strings.Join(commitInfoPlaceholders, "%x00")
+
+// https://git-scm.com/docs/git-log#_pretty_formats
+var commitInfoPlaceholders = []string{
+	"%H", "%h", "%T", "%P",
+	"%aN", "%aE", "%aI",
+	"%cN", "%cE", "%cI",
+	"%s", "%b", "%B",
+}
+
+func parseCommitInfo(fields []string) (*CommitInfo, error) {
+	if len(fields) != len(commitInfoPlaceholders) {
+		return nil, fmt.Errorf("invalid number of commit info fields: %d", len(fields))
+	}
+
+	authorDate, err := time.Parse(time.RFC3339, fields[6])
+	if err != nil {
+		return nil, err
+	}
+	committerDate, err := time.Parse(time.RFC3339, fields[9])
+	if err != nil {
+		return nil, err
+	}
+
+	info := CommitInfo{
+		Hash:           fields[0],
+		ShortHash:      fields[1],
+		TreeHash:       fields[2],
+		ParentHashes:   strings.Fields(fields[3]),
+		AuthorName:     fields[4],
+		AuthorEmail:    fields[5],
+		AuthorDate:     authorDate,
+		CommitterName:  fields[7],
+		CommitterEmail: fields[8],
+		CommitterDate:  committerDate,
+		Subject:        strings.TrimSpace(fields[10]),
+		Body:           strings.TrimSpace(fields[11]),
+		RawMessage:     fields[12],
+	}
+	return &info, nil
+}
+
+// GetInfo returns the metadata of the commit. It is loaded using a single git
+// command on first access and cached afterwards.
+func (c *Commit) GetInfo() (*CommitInfo, error) {
+	if c.info != nil {
+		return c.info, nil
+	}
+
+	// git show --no-patch -z --format=<commitInfoFormat> <hash>
+	command := script.LocalCommandFrom("git show --no-patch -z")
+	command.AddAll(commitInfoFormat, c.GetHash())
+
+	pr, err := c.repository.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not get log data for commit %s: %w", c.GetHash(), err)
+	}
+
+	fields := strings.Split(pr.Output(), "\x00")
+	if len(fields) < len(commitInfoPlaceholders) {
+		return nil, fmt.Errorf("could not parse log data for commit %s", c.GetHash())
+	}
+	info, err := parseCommitInfo(fields[:len(commitInfoPlaceholders)])
+	if err != nil {
+		return nil, fmt.Errorf("could not parse log data for commit %s: %w", c.GetHash(), err)
+	}
+
+	// put to cache
+	c.info = info
+
+	return info, nil
+}
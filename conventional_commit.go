@@ -0,0 +1,257 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// ConventionalCommit is a commit message following the Conventional Commits
+// specification.
+// https://www.conventionalcommits.org/en/v1.0.0/
+type ConventionalCommit struct {
+	// Commit is nil for messages parsed by ParseConventionalCommit
+	Commit *Commit
+	// Type in lower case, e.g. "feat" or "fix"
+	Type  string
+	Scope string
+	// Breaking is set by a "!" in front of the colon or a BREAKING CHANGE footer
+	Breaking    bool
+	Description string
+	Body        string
+	Footers     []ConventionalFooter
+}
+
+// ConventionalFooter is a trailer like "Refs: #123" or "BREAKING CHANGE: ...".
+type ConventionalFooter struct {
+	Token string
+	Value string
+}
+
+// ReleaseOptions configures Repository.AnalyzeRelease.
+type ReleaseOptions struct {
+	// Revision to be released, HEAD if empty
+	Revision string
+	// Version selects the last release, its ReachableFrom is set to Revision
+	Version VersionOptions
+}
+
+// UnparseableCommit is a commit whose message is no conventional commit.
+type UnparseableCommit struct {
+	Commit *Commit
+	Err    error
+}
+
+// ReleaseAnalysis contains the commits since the last release and the
+// resulting version bump.
+type ReleaseAnalysis struct {
+	// LastRelease is nil if there is no version tag yet
+	LastRelease *VersionTag
+	Commits     []*ConventionalCommit
+	Unparseable []*UnparseableCommit
+	// Bump is only valid if HasRelevantChanges is true
+	Bump               VersionBump
+	HasRelevantChanges bool
+	// NextVersion is the version implied by Bump, nil without relevant changes
+	NextVersion *semver.Version
+}
+
+// type(scope)!: description
+var regexpConventionalHeader = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*)(?:\(([^()\r\n]*)\))?(!)?: (\S.*)$`)
+
+// token: value or token #value, BREAKING CHANGE is the only token with a space
+var regexpConventionalFooter = regexp.MustCompile(`^(BREAKING CHANGE|[A-Za-z0-9-]+)(?:: (.*)| (#.*))$`)
+
+// ParseConventionalCommit parses a commit message. ErrNotConventional is returned
+// if the header does not follow the specification.
+func ParseConventionalCommit(message string) (*ConventionalCommit, error) {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	lines := strings.Split(message, "\n")
+
+	matches := regexpConventionalHeader.FindStringSubmatch(lines[0])
+	if matches == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotConventional, lines[0])
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		return nil, fmt.Errorf("%w: no blank line after header %q", ErrNotConventional, lines[0])
+	}
+
+	c := ConventionalCommit{
+		Type:        strings.ToLower(matches[1]),
+		Scope:       strings.TrimSpace(matches[2]),
+		Breaking:    matches[3] == "!",
+		Description: strings.TrimSpace(matches[4]),
+		Footers:     []ConventionalFooter{},
+	}
+
+	// the footers are the last paragraph if it starts with a token
+	rest := strings.TrimSpace(strings.Join(lines[1:], "\n"))
+	paragraphs := strings.Split(rest, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if regexpConventionalFooter.MatchString(strings.SplitN(last, "\n", 2)[0]) {
+		c.Footers = parseConventionalFooters(last)
+		paragraphs = paragraphs[:len(paragraphs)-1]
+	}
+	c.Body = strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+
+	for _, footer := range c.Footers {
+		if footer.IsBreakingChange() {
+			c.Breaking = true
+		}
+	}
+	return &c, nil
+}
+
+// parseConventionalFooters parses footer lines, lines without a token continue
+// the value of the previous footer.
+func parseConventionalFooters(paragraph string) []ConventionalFooter {
+	footers := make([]ConventionalFooter, 0)
+	for _, line := range strings.Split(paragraph, "\n") {
+		matches := regexpConventionalFooter.FindStringSubmatch(line)
+		if matches == nil {
+			footers[len(footers)-1].Value += "\n" + line
+			continue
+		}
+		footers = append(footers, ConventionalFooter{
+			Token: matches[1],
+			// the # is kept for references like "Refs #123"
+			Value: matches[2] + matches[3],
+		})
+	}
+	for i := range footers {
+		footers[i].Value = strings.TrimSpace(footers[i].Value)
+	}
+	return footers
+}
+
+// IsBreakingChange returns true for BREAKING CHANGE and its synonym BREAKING-CHANGE.
+func (f ConventionalFooter) IsBreakingChange() bool {
+	return f.Token == "BREAKING CHANGE" || f.Token == "BREAKING-CHANGE"
+}
+
+// GetBump returns the version bump implied by the commit: BumpMajor for breaking
+// changes, BumpMinor for features and BumpPatch for fixes. Other types do not
+// require a release, ok is false for them.
+func (c *ConventionalCommit) GetBump() (bump VersionBump, ok bool) {
+	switch {
+	case c.Breaking:
+		return BumpMajor, true
+	case c.Type == "feat":
+		return BumpMinor, true
+	case c.Type == "fix":
+		return BumpPatch, true
+	}
+	return BumpPatch, false
+}
+
+func (c *ConventionalCommit) String() string {
+	header := c.Type
+	if c.Scope != "" {
+		header += "(" + c.Scope + ")"
+	}
+	if c.Breaking {
+		header += "!"
+	}
+	return header + ": " + c.Description
+}
+
+// ParseConventionalCommit parses the message of the commit.
+func (c *Commit) ParseConventionalCommit() (*ConventionalCommit, error) {
+	message, err := c.GetRawMessage()
+	if err != nil {
+		return nil, err
+	}
+	conventional, err := ParseConventionalCommit(message)
+	if err != nil {
+		return nil, err
+	}
+	conventional.Commit = c
+	return conventional, nil
+}
+
+// AnalyzeRelease classifies the non-merge commits since the last version tag
+// reachable from the revision and computes the version bump they imply.
+// Commits not following the specification are listed in Unparseable.
+func (r *Repository) AnalyzeRelease(opts ReleaseOptions) (*ReleaseAnalysis, error) {
+	revision := opts.Revision
+	if revision == "" {
+		revision = "HEAD"
+	}
+	versionOpts := opts.Version
+	versionOpts.ReachableFrom = revision
+
+	analysis := ReleaseAnalysis{
+		Commits:     []*ConventionalCommit{},
+		Unparseable: []*UnparseableCommit{},
+	}
+	current := semver.MustParse("0.0.0")
+	revisionRange := revision
+	latest, err := r.GetLatestVersion(versionOpts)
+	switch {
+	case err == nil:
+		analysis.LastRelease = latest
+		current = latest.Version
+		revisionRange = latest.Tag.GetFullName() + ".." + revision
+	case !errors.Is(err, ErrNoVersionTag):
+		return nil, err
+	}
+
+	it, err := r.Log(LogOptions{
+		Revisions: []string{revisionRange},
+		NoMerges:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	commits, err := it.All()
+	if err != nil {
+		return nil, fmt.Errorf("could not list commits since last release: %w", err)
+	}
+
+	for _, commit := range commits {
+		conventional, err := commit.ParseConventionalCommit()
+		if errors.Is(err, ErrNotConventional) {
+			analysis.Unparseable = append(analysis.Unparseable, &UnparseableCommit{
+				Commit: commit,
+				Err:    err,
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		analysis.Commits = append(analysis.Commits, conventional)
+
+		bump, ok := conventional.GetBump()
+		if !ok {
+			continue
+		}
+		if !analysis.HasRelevantChanges || bumpRank(bump) > bumpRank(analysis.Bump) {
+			analysis.Bump = bump
+		}
+		analysis.HasRelevantChanges = true
+	}
+
+	if analysis.HasRelevantChanges {
+		analysis.NextVersion, err = NextVersion(current, analysis.Bump, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &analysis, nil
+}
+
+func bumpRank(bump VersionBump) int {
+	switch bump {
+	case BumpMajor:
+		return 3
+	case BumpMinor:
+		return 2
+	case BumpPatch:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected *ConventionalCommit
+	}{
+		{
+			message: "feat: add tags",
+			expected: &ConventionalCommit{
+				Type: "feat", Description: "add tags", Footers: []ConventionalFooter{},
+			},
+		},
+		{
+			message: "Fix(core)!: drop support\n",
+			expected: &ConventionalCommit{
+				Type: "fix", Scope: "core", Breaking: true, Description: "drop support", Footers: []ConventionalFooter{},
+			},
+		},
+		{
+			message: "docs: explain\n\nFirst paragraph.\n\nSecond paragraph.\n\nBREAKING CHANGE: api removed\n  continued\nRefs #12\nReviewed-by: Someone",
+			expected: &ConventionalCommit{
+				Type:        "docs",
+				Breaking:    true,
+				Description: "explain",
+				Body:        "First paragraph.\n\nSecond paragraph.",
+				Footers: []ConventionalFooter{
+					{Token: "BREAKING CHANGE", Value: "api removed\n  continued"},
+					{Token: "Refs", Value: "#12"},
+					{Token: "Reviewed-by", Value: "Someone"},
+				},
+			},
+		},
+		{
+			message: "refactor: x\r\n\r\nBREAKING-CHANGE: renamed",
+			expected: &ConventionalCommit{
+				Type: "refactor", Breaking: true, Description: "x",
+				Footers: []ConventionalFooter{{Token: "BREAKING-CHANGE", Value: "renamed"}},
+			},
+		},
+		{
+			message: "chore: body only\n\nNot a footer: as it continues\nbelow",
+			expected: &ConventionalCommit{
+				Type: "chore", Description: "body only", Footers: []ConventionalFooter{},
+				Body: "Not a footer: as it continues\nbelow",
+			},
+		},
+		{message: "random stuff"},
+		{message: "feat:missing space"},
+		{message: "feat(scope: broken"},
+		{message: "feat: no blank line\nafter header"},
+		{message: ""},
+	}
+	for _, test := range tests {
+		actual, err := ParseConventionalCommit(test.message)
+		if test.expected == nil {
+			if !errors.Is(err, ErrNotConventional) {
+				t.Errorf("%q: expected ErrNotConventional, got %v", test.message, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.message, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%q: expected %+v, got %+v", test.message, test.expected, actual)
+		}
+	}
+}
+
+func TestConventionalCommitBump(t *testing.T) {
+	tests := []struct {
+		message string
+		bump    VersionBump
+		ok      bool
+	}{
+		{"feat: x", BumpMinor, true},
+		{"fix: x", BumpPatch, true},
+		{"chore!: x", BumpMajor, true},
+		{"feat: x\n\nBREAKING CHANGE: y", BumpMajor, true},
+		{"docs: x", BumpPatch, false},
+	}
+	for _, test := range tests {
+		c, err := ParseConventionalCommit(test.message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bump, ok := c.GetBump()
+		if ok != test.ok || (ok && bump != test.bump) {
+			t.Errorf("%q: expected %s (%v), got %s (%v)", test.message, test.bump, test.ok, bump, ok)
+		}
+	}
+}
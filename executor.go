@@ -0,0 +1,155 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// Executor runs commands on behalf of a Repository. Implementations must be safe
+// for concurrent use, the working directory is passed on every call. When ctx is
+// done, the command must be aborted and ctx.Err() returned.
+type Executor interface {
+	Execute(ctx context.Context, invocation *Invocation) (*Result, error)
+}
+
+// Invocation describes a single command to be run by an Executor.
+type Invocation struct {
+	Dir     string
+	Command script.Command
+	// Env contains additional environment variables in the form key=value.
+	Env []string
+	// Stdin is passed to the standard input of the command if set.
+	Stdin io.Reader
+	// Stdout receives the standard output while the command is running if set.
+	// The output is not contained in the Result then. Executors may ignore it
+	// and return the output in the Result instead.
+	Stdout io.Writer
+}
+
+// Result contains the outcome of a command run by an Executor.
+type Result struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+// NewResult creates a Result, useful for custom Executor implementations.
+func NewResult(stdout, stderr string, exitCode int) *Result {
+	r := Result{
+		stdout:   stdout,
+		stderr:   stderr,
+		exitCode: exitCode,
+	}
+	return &r
+}
+
+// Output returns the stdout output of the command.
+func (r *Result) Output() string {
+	if r == nil {
+		return ""
+	}
+	return r.stdout
+}
+
+// TrimmedOutput returns the stdout output of the command with surrounding whitespace removed.
+func (r *Result) TrimmedOutput() string {
+	return strings.TrimSpace(r.Output())
+}
+
+// ErrorOutput returns the stderr output of the command.
+func (r *Result) ErrorOutput() string {
+	if r == nil {
+		return ""
+	}
+	return r.stderr
+}
+
+// ExitCode returns the exit code of the command, -1 if it is unknown.
+func (r *Result) ExitCode() int {
+	if r == nil {
+		return -1
+	}
+	return r.exitCode
+}
+
+// Successful returns true iff the command exited with code 0.
+func (r *Result) Successful() bool {
+	return r.ExitCode() == 0
+}
+
+type localExecutor struct{}
+
+// NewLocalExecutor returns the default Executor running commands as local processes.
+// The processes have no access to the terminal, so git fails instead of prompting
+// for credentials or passphrases.
+func NewLocalExecutor() Executor {
+	return &localExecutor{}
+}
+
+func (e *localExecutor) Execute(ctx context.Context, invocation *Invocation) (*Result, error) {
+	var (
+		stdout  bytes.Buffer
+		stderr  bytes.Buffer
+		exitErr *exec.ExitError
+	)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	command := invocation.Command
+	cmd := exec.Command(command.Binary(), command.Args()...)
+	cmd.Dir = invocation.Dir
+	// unlocalized output for reliable parsing, no credential prompts blocking
+	// forever without a terminal
+	cmd.Env = append(os.Environ(), "LC_ALL=C", "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, invocation.Env...)
+	cmd.Stdin = invocation.Stdin
+	cmd.Stdout = &stdout
+	if invocation.Stdout != nil {
+		cmd.Stdout = invocation.Stdout
+	}
+	cmd.Stderr = &stderr
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		// a missing working directory would be reported as missing binary
+		if _, statErr := os.Stat(invocation.Dir); invocation.Dir != "" && statErr != nil {
+			return nil, statErr
+		}
+		return nil, err
+	}
+
+	// kill the whole process group on cancellation, so that helpers started by
+	// git like ssh do not keep the output pipes open
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-done:
+		}
+	}()
+	err := cmd.Wait()
+	close(done)
+
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if errors.As(err, &exitErr) {
+		// unsuccessful commands are reported via the Result
+		return NewResult(stdout.String(), stderr.String(), exitErr.ExitCode()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResult(stdout.String(), stderr.String(), 0), nil
+}
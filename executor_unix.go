@@ -0,0 +1,25 @@
+//go:build !windows
+
+package git
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in a new session, which is a new process group
+// without controlling terminal. Prompts of git or ssh reading /dev/tty fail
+// instead of stopping the background process group with SIGTTIN.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid: true,
+	}
+}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	// negative pid addresses the process group
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
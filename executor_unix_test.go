@@ -0,0 +1,32 @@
+//go:build !windows
+
+package git
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+func TestLocalExecutorWithoutTerminal(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not installed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	command := script.LocalCommandFrom("sh -c")
+	command.Add(`echo "$GIT_TERMINAL_PROMPT"; if (exec </dev/tty) 2>/dev/null; then echo tty; else echo no tty; fi`)
+	result, err := NewLocalExecutor().Execute(ctx, &Invocation{Command: command})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(result.TrimmedOutput(), "\n")
+	if len(lines) != 2 || lines[0] != "0" || lines[1] != "no tty" {
+		t.Errorf("expected disabled prompts without terminal, got %q", result.Output())
+	}
+}
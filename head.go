@@ -0,0 +1,125 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// Head describes the state of HEAD: it either points to a local branch, which
+// may be unborn in a repository without commits, or to a detached commit.
+type Head struct {
+	// nil if detached
+	branch *LocalBranch
+	// nil if unborn
+	commit *Commit
+}
+
+// IsDetached returns true iff HEAD points to a commit instead of a branch.
+func (h *Head) IsDetached() bool {
+	return h.branch == nil
+}
+
+// IsUnborn returns true iff HEAD points to a branch without any commits yet.
+func (h *Head) IsUnborn() bool {
+	return h.commit == nil
+}
+
+// GetBranch returns the current branch, nil if HEAD is detached.
+func (h *Head) GetBranch() *LocalBranch {
+	return h.branch
+}
+
+// GetCommit returns the current commit, nil if the current branch is unborn.
+func (h *Head) GetCommit() *Commit {
+	return h.commit
+}
+
+func (h *Head) String() string {
+	switch {
+	case h.IsDetached():
+		return "HEAD detached at " + h.commit.GetHash()
+	case h.IsUnborn():
+		return "HEAD at unborn " + h.branch.GetName()
+	default:
+		return "HEAD at " + h.branch.GetName()
+	}
+}
+
+// GetHead returns the state of HEAD.
+func (r *Repository) GetHead() (*Head, error) {
+	branchName, err := r.resolveHeadBranchName()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := r.resolveHeadCommit()
+	if err != nil {
+		return nil, err
+	}
+
+	head := Head{
+		commit: commit,
+	}
+	if branchName != "" {
+		head.branch = newLocalBranch(r, branchName)
+	}
+	return &head, nil
+}
+
+// IsDetachedHead returns true iff HEAD points to a commit instead of a branch.
+func (r *Repository) IsDetachedHead() (bool, error) {
+	branchName, err := r.resolveHeadBranchName()
+	if err != nil {
+		return false, err
+	}
+	return branchName == "", nil
+}
+
+// IsUnbornHead returns true iff HEAD points to a branch without any commits yet,
+// as it is the case in freshly initialized repositories.
+func (r *Repository) IsUnbornHead() (bool, error) {
+	commit, err := r.resolveHeadCommit()
+	if err != nil {
+		return false, err
+	}
+	return commit == nil, nil
+}
+
+// resolveHeadBranchName returns the name of the branch HEAD points to, "" if HEAD
+// is detached.
+func (r *Repository) resolveHeadBranchName() (string, error) {
+	// https://git-scm.com/docs/git-symbolic-ref
+	// git symbolic-ref --quiet HEAD
+	command := script.LocalCommandFrom("git symbolic-ref --quiet HEAD")
+	pr, err := r.Execute(command)
+	if err != nil {
+		return "", err
+	}
+	// exit code 1 means HEAD is not a symbolic ref
+	if pr.ExitCode() == 1 {
+		return "", nil
+	}
+	if !pr.Successful() {
+		return "", fmt.Errorf("could not resolve HEAD: %w", newGitCommandError(command, pr))
+	}
+	return strings.TrimPrefix(pr.TrimmedOutput(), "refs/heads/"), nil
+}
+
+// resolveHeadCommit returns the commit HEAD points to, nil if it is unborn.
+func (r *Repository) resolveHeadCommit() (*Commit, error) {
+	// git rev-parse --verify --quiet HEAD^{commit}
+	command := script.LocalCommandFrom("git rev-parse --verify --quiet HEAD^{commit}")
+	pr, err := r.Execute(command)
+	if err != nil {
+		return nil, err
+	}
+	// exit code 1 means HEAD could not be resolved
+	if pr.ExitCode() == 1 {
+		return nil, nil
+	}
+	if !pr.Successful() {
+		return nil, fmt.Errorf("could not resolve HEAD commit: %w", newGitCommandError(command, pr))
+	}
+	return newCommit(r, pr.TrimmedOutput())
+}
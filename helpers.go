@@ -1,42 +1,54 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/jojomi/go-script/v2"
 )
 
-// cache
-var gitVersion *semver.Version
+// cache of GetGitVersion
+var (
+	gitVersionMutex sync.Mutex
+	gitVersion      *semver.Version
+)
 
+// GetGitVersion returns the version of the local git binary. It is safe for
+// concurrent use, repositories use the version reported by their Executor instead.
 func GetGitVersion() (*semver.Version, error) {
+	gitVersionMutex.Lock()
+	defer gitVersionMutex.Unlock()
+
 	if gitVersion != nil {
 		return gitVersion, nil
 	}
 
-	sc := script.NewContext()
-	sc.MustCommandExist("git")
+	// git version
 	command := script.LocalCommandFrom("git version")
-	pr, err := sc.ExecuteSilent(command)
+	pr, err := NewLocalExecutor().Execute(context.Background(), &Invocation{
+		Command: command,
+	})
+	if err != nil {
+		return &semver.Version{}, err
+	}
 	if !pr.Successful() {
-		err = fmt.Errorf("version command failed")
+		return &semver.Version{}, newGitCommandError(command, pr)
 	}
+
+	version, err := parseGitVersion(pr.Output())
 	if err != nil {
 		return &semver.Version{}, err
 	}
-	v := pr.Output()
-	v = strings.Replace(v, "git version", "", 1)
-	v = strings.TrimSpace(v)
 
-	version, err := semver.NewVersion(v)
+	// put to cache
+	gitVersion = version
 
-	if err == nil {
-		gitVersion = version
-	}
-	return version, err
+	return version, nil
 }
 
 func MustGetGitVersion() *semver.Version {
@@ -47,6 +59,68 @@ func MustGetGitVersion() *semver.Version {
 	return version
 }
 
+// gitVersionCache holds the git version reported by the Executor of a
+// Repository, it is shared by copies of the Repository.
+type gitVersionCache struct {
+	mutex   sync.Mutex
+	version *semver.Version
+}
+
+// getGitVersion returns the version of git as run by the Executor of r.
+func (r *Repository) getGitVersion() (*semver.Version, error) {
+	if r.versionCache != nil {
+		r.versionCache.mutex.Lock()
+		defer r.versionCache.mutex.Unlock()
+		if r.versionCache.version != nil {
+			return r.versionCache.version, nil
+		}
+	}
+
+	// git version
+	command := script.LocalCommandFrom("git version")
+	pr, err := r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not get git version: %w", err)
+	}
+	version, err := parseGitVersion(pr.Output())
+	if err != nil {
+		return nil, err
+	}
+
+	// put to cache
+	if r.versionCache != nil {
+		r.versionCache.version = version
+	}
+
+	return version, nil
+}
+
+// checkGitVersion returns true iff the git version of r satisfies constraint
+// like ">= 2.23".
+func (r *Repository) checkGitVersion(constraint string) (bool, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	v, err := r.getGitVersion()
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+// e.g. "git version 2.39.2", "git version 2.39.2.windows.1" or
+// "git version 2.37.1 (Apple Git-137.1)"
+var regexpGitVersion = regexp.MustCompile(`git version ([0-9]+\.[0-9]+(?:\.[0-9]+)?)`)
+
+func parseGitVersion(output string) (*semver.Version, error) {
+	matches := regexpGitVersion.FindStringSubmatch(output)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid git version output: %s", strings.TrimSpace(output))
+	}
+	return semver.NewVersion(matches[1])
+}
+
 var regexpStarredBranchList = regexp.MustCompile(`^\s*\*?\s*([^ ]*)`)
 var regexpBranchList = regexp.MustCompile(`^[0-9a-f]{5,40}\s+(.*)$`)
 
@@ -84,3 +158,21 @@ func parseBranchList(input string) ([]string, error) {
 	}
 	return branches, nil
 }
+
+// identityEnv returns the environment variables overriding an identity, role is
+// AUTHOR or COMMITTER. Empty values are not overridden.
+// https://git-scm.com/book/en/v2/Git-Internals-Environment-Variables
+func identityEnv(role, name, email string, date time.Time) []string {
+	env := make([]string, 0, 3)
+	if name != "" {
+		env = append(env, "GIT_"+role+"_NAME="+name)
+	}
+	if email != "" {
+		env = append(env, "GIT_"+role+"_EMAIL="+email)
+	}
+	if !date.IsZero() {
+		// internal date format, keeping the time zone
+		env = append(env, fmt.Sprintf("GIT_%s_DATE=@%d %s", role, date.Unix(), date.Format("-0700")))
+	}
+	return env
+}
@@ -1,7 +1,6 @@
 package git
 
 import (
-	"bufio"
 	"fmt"
 	"strings"
 
@@ -9,10 +8,12 @@ import (
 )
 
 type LocalBranch struct {
-	name               string
-	trackingRemoteName string
+	name string
 
 	repository *Repository
+
+	// cached values
+	info *BranchInfo
 }
 
 func newLocalBranch(repository *Repository, name string) *LocalBranch {
@@ -31,16 +32,19 @@ func (b *LocalBranch) GetFullName() string {
 	return b.name
 }
 
+// GetHeadCommit returns the commit the branch points to. For branches with
+// metadata like those retrieved by ListBranches, it is taken from the snapshot.
 func (b *LocalBranch) GetHeadCommit() (*Commit, error) {
+	if b.info != nil {
+		return newCommit(b.repository, b.info.HeadHash)
+	}
+
 	command := script.LocalCommandFrom("git rev-parse")
 	command.Add(b.GetName())
 
-	pr, err := b.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("getting HEAD commit faile for LocalBranch %s", b.GetName())
-	}
+	pr, err := b.repository.run(command)
 	if err != nil {
-		return &Commit{}, err
+		return &Commit{}, fmt.Errorf("could not get HEAD commit for local branch %s: %w", b.GetName(), err)
 	}
 
 	hash := strings.TrimSpace(pr.Output())
@@ -57,87 +61,89 @@ func (b *LocalBranch) IsMainBranch() (bool, error) {
 }
 
 func (b *LocalBranch) IsMergedTo(target Branch) (bool, error) {
-	localBranchHead, err := b.GetHeadCommit()
-	if err != nil {
-		return false, err
-	}
-	targetHead, err := target.GetHeadCommit()
+	return isMergedTo(b.repository, b, target)
+}
+
+// GetCommitsByMessage returns the non-merge commits on the branch whose message
+// contains msg literally.
+func (b *LocalBranch) GetCommitsByMessage(msg string) ([]*Commit, error) {
+	return b.SearchCommits(SearchOptions{
+		Patterns:  []string{msg},
+		MatchMode: MatchFixed,
+	})
+}
+
+// SearchCommits returns the commits on the branch whose message matches.
+func (b *LocalBranch) SearchCommits(opts SearchOptions) ([]*Commit, error) {
+	result, err := searchCommits(b.repository, "refs/heads/"+b.GetName(), opts)
 	if err != nil {
-		return false, err
+		return []*Commit{}, fmt.Errorf("could not search commits on branch %s: %w", b.GetFullName(), err)
 	}
+	return result, nil
+}
 
-	// both have the same HEAD commit? -> merged by definition
-	if localBranchHead.Equals(targetHead) {
-		return true, nil
+// GetTrackingRemote returns the remote branch the branch is tracking.
+//
+// Deprecated: The remote is derived from the configuration now, use GetUpstream.
+func (b *LocalBranch) GetTrackingRemote(r *Remote) (*RemoteBranch, error) {
+	upstream, err := b.GetUpstream()
+	if err != nil {
+		return nil, err
 	}
-
-	// https://stackoverflow.com/a/40011122
-	// git merge-base <commit-hash-step1> <commit-hash-step2>
-	command := script.LocalCommandFrom("git merge-base")
-	command.AddAll(localBranchHead.GetHash(), targetHead.GetHash())
-
-	pr, err := b.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not find merge-base between %s and %s", localBranchHead.GetHash(), targetHead.GetHash())
+	remoteBranch, ok := upstream.(*RemoteBranch)
+	if !ok {
+		return nil, fmt.Errorf("upstream of %s is local branch %s", b.GetName(), upstream.GetName())
 	}
-	if err != nil {
-		return false, err
+	if r != nil && remoteBranch.GetRemote().GetName() != r.GetName() {
+		return nil, fmt.Errorf("upstream of %s is on remote %s instead of %s", b.GetName(), remoteBranch.GetRemote().GetName(), r.GetName())
 	}
-	mergeBase := strings.TrimSpace(pr.Output())
-	return mergeBase == localBranchHead.GetHash(), nil
+	return remoteBranch, nil
 }
 
-func (b *LocalBranch) GetCommitsByMessage(msg string) ([]*Commit, error) {
-	lc := &script.LocalCommand{}
-	// TODO replace regexp meta chars on msg
-	lc.AddAll("git", "log", `--pretty=%h`, "--no-merges", b.GetFullName(), "--grep", msg)
-	pr, err := b.repository.Execute(lc)
-
-	result := []*Commit{}
+// GetUpstream returns the configured upstream branch, which is a *RemoteBranch
+// or a *LocalBranch for upstreams in the same repository. ErrNoUpstream is
+// returned if there is none.
+func (b *LocalBranch) GetUpstream() (Branch, error) {
+	remoteName, err := b.repository.getConfigValue("branch." + b.GetName() + ".remote")
 	if err != nil {
-		return result, err
+		return nil, err
+	}
+	mergeRef, err := b.repository.getConfigValue("branch." + b.GetName() + ".merge")
+	if err != nil {
+		return nil, err
 	}
-	if !pr.Successful() {
-		return result, fmt.Errorf(`could not execute search for commit message "%s" on branch %s`, msg, b.GetFullName())
+	if remoteName == "" || mergeRef == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNoUpstream, b.GetName())
 	}
 
-	// scan output line by line
-	scanner := bufio.NewScanner(strings.NewReader(pr.TrimmedOutput()))
-	for scanner.Scan() {
-		commit, err := newCommit(b.repository, scanner.Text())
-		if err != nil {
-			return result, err
-		}
-		result = append(result, commit)
+	// upstream in the same repository
+	if remoteName == "." {
+		return newLocalBranch(b.repository, strings.TrimPrefix(mergeRef, "refs/heads/")), nil
 	}
-	return result, nil
-}
 
-func (b *LocalBranch) GetTrackingRemote(r *Remote) (*RemoteBranch, error) {
-	if b.trackingRemoteName != "" {
-		return newRemoteBranch(b.repository, r, b.trackingRemoteName), nil
+	remote, err := b.repository.GetRemote(remoteName)
+	if err != nil {
+		return nil, fmt.Errorf("could not get upstream of %s: %w", b.GetName(), err)
 	}
 
+	// the remote-tracking branch depends on the fetch refspec of the remote
 	// https://serverfault.com/a/384862
-	// git rev-parse --symbolic-full-name master@{u}
+	// git rev-parse --symbolic-full-name <name>@{u}
 	command := script.LocalCommandFrom("git rev-parse --symbolic-full-name")
-	command.Add(b.GetName() + "@{u}")
-
+	command.Add("refs/heads/" + b.GetName() + "@{u}")
 	pr, err := b.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not find tracking LocalBranch for %s", b.GetName())
-	}
 	if err != nil {
 		return nil, err
 	}
-
-	trackingName := strings.Replace(strings.TrimSpace(pr.Output()), "refs/remotes/", "", 1)
-
-	if trackingName != "" {
-		b.trackingRemoteName = trackingName
+	prefix := "refs/remotes/" + remote.GetName() + "/"
+	trackingRef := pr.TrimmedOutput()
+	if pr.Successful() && strings.HasPrefix(trackingRef, prefix) {
+		return newRemoteBranch(b.repository, remote, strings.TrimPrefix(trackingRef, prefix)), nil
 	}
 
-	return newRemoteBranch(b.repository, r, trackingName), nil
+	// Fallback, e.g. if the upstream is gone
+	// default refspec maps refs/heads/<name> to refs/remotes/<remote>/<name>
+	return newRemoteBranch(b.repository, remote, strings.TrimPrefix(mergeRef, "refs/heads/")), nil
 }
 
 func (b *LocalBranch) deleteInternal(force bool) error {
@@ -148,12 +154,9 @@ func (b *LocalBranch) deleteInternal(force bool) error {
 	}
 	command.Add(b.GetName())
 
-	pr, err := b.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not delete LocalBranch %s", b.GetName())
-	}
+	_, err := b.repository.run(command)
 	if err != nil {
-		return err
+		return fmt.Errorf("could not delete local branch %s: %w", b.GetName(), err)
 	}
 
 	return nil
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGetUpstream(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	git("commit", "--quiet", "--allow-empty", "-m", "init")
+	git("remote", "add", "origin", "https://example.com/repo.git")
+	git("update-ref", "refs/remotes/origin/feature", "main")
+	git("branch", "--track", "feature", "origin/feature")
+	git("branch", "--track", "local", "main")
+	git("branch", "none")
+	// the remote-tracking branch was pruned
+	git("branch", "gone")
+	git("config", "branch.gone.remote", "origin")
+	git("config", "branch.gone.merge", "refs/heads/gone")
+
+	tests := []struct {
+		branch   string
+		remote   string
+		expected string
+	}{
+		{"feature", "origin", "origin/feature"},
+		{"gone", "origin", "origin/gone"},
+		{"local", "", "main"},
+	}
+	for _, test := range tests {
+		branch, err := r.GetBranch(test.branch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		upstream, err := branch.GetUpstream()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.branch, err)
+			continue
+		}
+		if upstream.GetFullName() != test.expected {
+			t.Errorf("%s: expected upstream %s, got %s", test.branch, test.expected, upstream.GetFullName())
+		}
+		switch upstream := upstream.(type) {
+		case *RemoteBranch:
+			if upstream.GetRemote().GetName() != test.remote {
+				t.Errorf("%s: expected remote %s, got %s", test.branch, test.remote, upstream.GetRemote().GetName())
+			}
+		case *LocalBranch:
+			if test.remote != "" {
+				t.Errorf("%s: expected remote branch, got %s", test.branch, upstream)
+			}
+		}
+	}
+
+	none, err := r.GetBranch("none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = none.GetUpstream()
+	if !errors.Is(err, ErrNoUpstream) {
+		t.Errorf("expected ErrNoUpstream, got %v", err)
+	}
+}
+
+func TestGetTrackingRemote(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	git("commit", "--quiet", "--allow-empty", "-m", "init")
+	git("remote", "add", "origin", "https://example.com/repo.git")
+	git("remote", "add", "other", "https://example.com/other.git")
+	git("update-ref", "refs/remotes/origin/feature", "main")
+	git("branch", "--track", "feature", "origin/feature")
+	git("branch", "--track", "local", "main")
+
+	origin, err := r.GetRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := r.GetRemote("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feature, err := r.GetBranch("feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, remote := range []*Remote{origin, nil} {
+		tracking, err := feature.GetTrackingRemote(remote)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tracking.GetFullName() != "origin/feature" || tracking.GetName() != "feature" {
+			t.Errorf("expected origin/feature, got %s", tracking.GetFullName())
+		}
+	}
+	_, err = feature.GetTrackingRemote(other)
+	if err == nil {
+		t.Error("expected error for a different remote")
+	}
+
+	local, err := r.GetBranch("local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = local.GetTrackingRemote(nil)
+	if err == nil {
+		t.Error("expected error for a local upstream")
+	}
+}
@@ -0,0 +1,271 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// MatchMode defines how patterns are matched against commit metadata.
+type MatchMode int
+
+const (
+	// MatchFixed matches patterns literally.
+	MatchFixed MatchMode = iota
+	// MatchBasicRegexp interprets patterns as POSIX basic regular expressions.
+	MatchBasicRegexp
+	// MatchExtendedRegexp interprets patterns as POSIX extended regular expressions.
+	MatchExtendedRegexp
+	// MatchPerlRegexp interprets patterns as Perl-compatible regular expressions,
+	// git must be built with PCRE support.
+	MatchPerlRegexp
+)
+
+func (m MatchMode) flag() (string, error) {
+	switch m {
+	case MatchFixed:
+		return "--fixed-strings", nil
+	case MatchBasicRegexp:
+		return "--basic-regexp", nil
+	case MatchExtendedRegexp:
+		return "--extended-regexp", nil
+	case MatchPerlRegexp:
+		return "--perl-regexp", nil
+	}
+	return "", fmt.Errorf("invalid match mode %d", m)
+}
+
+// LogOptions configures Repository.Log. The zero value lists all commits
+// reachable from HEAD.
+type LogOptions struct {
+	// Revisions like "main", "^v1.0.0" or "v1.0.0..HEAD", HEAD if empty
+	Revisions []string
+	// Paths limits the commits to those touching any of the paths
+	Paths []string
+
+	// Author and Committer are matched using MatchMode like Grep
+	Author    string
+	Committer string
+	Since     time.Time
+	Until     time.Time
+
+	// MaxCount limits the number of commits if > 0
+	MaxCount    int
+	Skip        int
+	FirstParent bool
+	NoMerges    bool
+
+	// Grep lists patterns to be matched against the commit message, any of
+	// them has to match unless AllMatch is set.
+	Grep       []string
+	MatchMode  MatchMode
+	IgnoreCase bool
+	AllMatch   bool
+}
+
+func (o LogOptions) args() ([]string, error) {
+	args := make([]string, 0, 10)
+
+	if o.Author != "" {
+		args = append(args, "--author="+o.Author)
+	}
+	if o.Committer != "" {
+		args = append(args, "--committer="+o.Committer)
+	}
+	if !o.Since.IsZero() {
+		args = append(args, "--since="+o.Since.Format(time.RFC3339))
+	}
+	if !o.Until.IsZero() {
+		args = append(args, "--until="+o.Until.Format(time.RFC3339))
+	}
+	if o.MaxCount > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(o.MaxCount))
+	}
+	if o.Skip > 0 {
+		args = append(args, "--skip="+strconv.Itoa(o.Skip))
+	}
+	if o.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	if o.NoMerges {
+		args = append(args, "--no-merges")
+	}
+	for _, pattern := range o.Grep {
+		args = append(args, "--grep="+pattern)
+	}
+	if o.AllMatch {
+		args = append(args, "--all-match")
+	}
+	if o.IgnoreCase {
+		args = append(args, "--regexp-ignore-case")
+	}
+	matchFlag, err := o.MatchMode.flag()
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, matchFlag)
+
+	for _, revision := range o.Revisions {
+		if strings.HasPrefix(revision, "-") {
+			return nil, fmt.Errorf("invalid revision %s", revision)
+		}
+		args = append(args, revision)
+	}
+
+	// always separate paths, so revisions are never mistaken for them
+	args = append(args, "--")
+	args = append(args, o.Paths...)
+
+	return args, nil
+}
+
+// Log lists commits like git log. The commits are streamed from git while
+// iterating, the iterator must be closed if it is not consumed completely.
+func (r *Repository) Log(opts LogOptions) (*CommitIterator, error) {
+	args, err := opts.args()
+	if err != nil {
+		return nil, err
+	}
+
+	// git log -z --format=<commitInfoFormat> [<options>] [<revisions>] -- [<paths>]
+	command := script.LocalCommandFrom("git log -z")
+	command.Add(commitInfoFormat)
+	command.AddAll(args...)
+
+	return newCommitIterator(r, command), nil
+}
+
+// CommitIterator iterates over commits as they are output by git:
+//
+//	it, err := repository.Log(LogOptions{})
+//	...
+//	defer it.Close()
+//	for it.Next() {
+//		commit := it.Commit()
+//	}
+//	err = it.Err()
+type CommitIterator struct {
+	repository *Repository
+
+	pipeReader *io.PipeReader
+	reader     *bufio.Reader
+	cancel     context.CancelFunc
+	done       chan struct{}
+
+	commit *Commit
+	err    error
+	closed bool
+}
+
+func newCommitIterator(repository *Repository, command script.Command) *CommitIterator {
+	ctx, cancel := context.WithCancel(repository.Context())
+	pipeReader, pipeWriter := io.Pipe()
+
+	it := CommitIterator{
+		repository: repository,
+		pipeReader: pipeReader,
+		reader:     bufio.NewReader(pipeReader),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	go func() {
+		defer close(it.done)
+		pr, err := repository.invoke(ctx, &Invocation{
+			Command: command,
+			Stdout:  pipeWriter,
+		})
+		if err == nil && !pr.Successful() {
+			err = newGitCommandError(command, pr)
+		}
+		// executors not streaming to Stdout return the output in the Result
+		if err == nil && pr.Output() != "" {
+			_, err = io.WriteString(pipeWriter, pr.Output())
+		}
+		// nil results in io.EOF for the reader
+		pipeWriter.CloseWithError(err)
+	}()
+
+	return &it
+}
+
+// Next advances to the next commit, it returns false when there are no more
+// commits or an error occurred.
+func (it *CommitIterator) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+
+	fields := make([]string, 0, len(commitInfoPlaceholders))
+	for len(fields) < len(commitInfoPlaceholders) {
+		field, err := it.reader.ReadString(0)
+		if errors.Is(err, io.EOF) && len(fields) == 0 && field == "" {
+			// regular end of output
+			it.Close()
+			return false
+		}
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+		fields = append(fields, strings.TrimSuffix(field, "\x00"))
+	}
+
+	info, err := parseCommitInfo(fields)
+	if err == nil {
+		it.commit, err = newCommitFromInfo(it.repository, info)
+	}
+	if err != nil {
+		it.err = err
+		it.Close()
+		return false
+	}
+	return true
+}
+
+// Commit returns the current commit, its metadata is already loaded.
+func (it *CommitIterator) Commit() *Commit {
+	return it.commit
+}
+
+// Err returns the first error that occurred while iterating.
+func (it *CommitIterator) Err() error {
+	return it.err
+}
+
+// Close aborts the git command if it is still running and releases all
+// resources. It is safe to call Close multiple times.
+func (it *CommitIterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+
+	it.cancel()
+	// unblock the executor in case it is still writing
+	it.pipeReader.Close()
+	<-it.done
+	return nil
+}
+
+// All consumes the iterator and returns all remaining commits.
+func (it *CommitIterator) All() ([]*Commit, error) {
+	defer it.Close()
+
+	commits := make([]*Commit, 0)
+	for it.Next() {
+		commits = append(commits, it.Commit())
+	}
+	return commits, it.Err()
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// bufferingExecutor runs commands locally but returns the output in the Result
+// instead of streaming it to Invocation.Stdout.
+type bufferingExecutor struct {
+	executor Executor
+}
+
+func (e *bufferingExecutor) Execute(ctx context.Context, invocation *Invocation) (*Result, error) {
+	buffered := *invocation
+	buffered.Stdout = nil
+	return e.executor.Execute(ctx, &buffered)
+}
+
+func TestLog(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	for _, message := range []string{"first", "second", "third"} {
+		git("commit", "--quiet", "--allow-empty", "-m", message)
+	}
+
+	buffered, err := OpenRepository(r.GetPath(), WithExecutor(&bufferingExecutor{executor: NewLocalExecutor()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, repository := range map[string]*Repository{"streaming": r, "buffering": buffered} {
+		it, err := repository.Log(LogOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits, err := it.All()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		subjects := make([]string, 0, len(commits))
+		for _, commit := range commits {
+			info, err := commit.GetInfo()
+			if err != nil {
+				t.Fatal(err)
+			}
+			subjects = append(subjects, info.Subject)
+		}
+		if len(subjects) != 3 || subjects[0] != "third" || subjects[2] != "first" {
+			t.Errorf("%s: unexpected commits %v", name, subjects)
+		}
+
+		// closing before consuming all commits
+		it, err = repository.Log(LogOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !it.Next() {
+			t.Errorf("%s: expected a commit, got %v", name, it.Err())
+		}
+		it.Close()
+
+		it, err = repository.Log(LogOptions{Revisions: []string{"missing"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = it.All()
+		var commandErr *GitCommandError
+		if !errors.As(err, &commandErr) {
+			t.Errorf("%s: expected GitCommandError for missing revision, got %v", name, err)
+		}
+	}
+}
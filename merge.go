@@ -0,0 +1,257 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// MergeMethod describes how the changes of a branch made it to the target branch.
+type MergeMethod int
+
+const (
+	NotMerged MergeMethod = iota
+	// MergedByAncestry means the branch HEAD is reachable from the target.
+	MergedByAncestry
+	// MergedByPatch means every commit has an equivalent patch on the target,
+	// as it is the case after rebasing or cherry-picking.
+	MergedByPatch
+	// MergedBySquash means the combined changes of the branch equal a single
+	// commit on the target.
+	MergedBySquash
+)
+
+func (m MergeMethod) String() string {
+	switch m {
+	case MergedByAncestry:
+		return "ancestry"
+	case MergedByPatch:
+		return "patch"
+	case MergedBySquash:
+		return "squash"
+	}
+	return "not merged"
+}
+
+// CommitMatch pairs a commit of a branch with its equivalent on the target.
+type CommitMatch struct {
+	Commit   *Commit
+	Upstream *Commit
+}
+
+// MergeReport is the result of an effective merge check. For MergedByAncestry
+// no commits are listed, otherwise Matches and Unmatched cover all non-merge
+// commits on the branch since the merge base that introduce changes.
+type MergeReport struct {
+	Merged    bool
+	Method    MergeMethod
+	Matches   []CommitMatch
+	Unmatched []*Commit
+}
+
+// patchIdEntry is a line of git patch-id output.
+type patchIdEntry struct {
+	patchId string
+	hash    string
+}
+
+// isEffectivelyMergedTo checks if source is merged to target by ancestry, by
+// equivalent patches (git cherry semantics) or as a single squash commit.
+func isEffectivelyMergedTo(repository *Repository, source, target Branch) (*MergeReport, error) {
+	report := MergeReport{
+		Matches:   []CommitMatch{},
+		Unmatched: []*Commit{},
+	}
+
+	merged, err := isMergedTo(repository, source, target)
+	if err != nil {
+		return nil, err
+	}
+	if merged {
+		report.Merged = true
+		report.Method = MergedByAncestry
+		return &report, nil
+	}
+
+	sourceHead, err := source.GetHeadCommit()
+	if err != nil {
+		return nil, err
+	}
+	targetHead, err := target.GetHeadCommit()
+	if err != nil {
+		return nil, err
+	}
+
+	// git merge-base <commit-hash-step1> <commit-hash-step2>
+	command := script.LocalCommandFrom("git merge-base")
+	command.AddAll(sourceHead.GetHash(), targetHead.GetHash())
+	pr, err := repository.Execute(command)
+	if err != nil {
+		return nil, err
+	}
+	// exit code 1 means there is no common history
+	if pr.ExitCode() == 1 {
+		return &report, nil
+	}
+	if !pr.Successful() {
+		return nil, newGitCommandError(command, pr)
+	}
+	mergeBase := pr.TrimmedOutput()
+	if !IsValidCommitHash(mergeBase) {
+		return nil, fmt.Errorf("%w: merge-base %s", ErrInvalidCommitHash, mergeBase)
+	}
+
+	sourcePatchIds, err := repository.getPatchIds(mergeBase, sourceHead.GetHash())
+	if err != nil {
+		return nil, err
+	}
+	targetPatchIds, err := repository.getPatchIds(mergeBase, targetHead.GetHash())
+	if err != nil {
+		return nil, err
+	}
+	upstreamByPatchId := make(map[string]string, len(targetPatchIds))
+	for _, entry := range targetPatchIds {
+		upstreamByPatchId[entry.patchId] = entry.hash
+	}
+
+	// rebased or cherry-picked commits
+	for _, entry := range sourcePatchIds {
+		commit, err := newCommit(repository, entry.hash)
+		if err != nil {
+			return nil, err
+		}
+		upstreamHash, found := upstreamByPatchId[entry.patchId]
+		if !found {
+			report.Unmatched = append(report.Unmatched, commit)
+			continue
+		}
+		upstream, err := newCommit(repository, upstreamHash)
+		if err != nil {
+			return nil, err
+		}
+		report.Matches = append(report.Matches, CommitMatch{
+			Commit:   commit,
+			Upstream: upstream,
+		})
+	}
+	if len(sourcePatchIds) > 0 && len(report.Unmatched) == 0 {
+		report.Merged = true
+		report.Method = MergedByPatch
+		return &report, nil
+	}
+
+	// squashed commits
+	diffPatchId, err := repository.getDiffPatchId(mergeBase, sourceHead.GetHash())
+	if err != nil {
+		return nil, err
+	}
+	upstreamHash, found := upstreamByPatchId[diffPatchId]
+	if diffPatchId == "" || !found {
+		return &report, nil
+	}
+	upstream, err := newCommit(repository, upstreamHash)
+	if err != nil {
+		return nil, err
+	}
+	report.Merged = true
+	report.Method = MergedBySquash
+	report.Matches = []CommitMatch{}
+	report.Unmatched = []*Commit{}
+	for _, entry := range sourcePatchIds {
+		commit, err := newCommit(repository, entry.hash)
+		if err != nil {
+			return nil, err
+		}
+		report.Matches = append(report.Matches, CommitMatch{
+			Commit:   commit,
+			Upstream: upstream,
+		})
+	}
+	return &report, nil
+}
+
+// getPatchIds returns the patch-ids of all non-merge commits in from..to, newest first.
+func (r *Repository) getPatchIds(from, to string) ([]patchIdEntry, error) {
+	err := validateRefArguments(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// git log -p --no-merges <from>..<to> | git patch-id
+	command := script.LocalCommandFrom("git log --patch --no-merges --format=commit%x20%H")
+	command.AddAll(from+".."+to, "--")
+	output, err := r.patchIds(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not get patch-ids for %s..%s: %w", from, to, err)
+	}
+
+	entries := make([]patchIdEntry, 0)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		entries = append(entries, patchIdEntry{
+			patchId: fields[0],
+			hash:    fields[1],
+		})
+	}
+	return entries, nil
+}
+
+// getDiffPatchId returns the patch-id of the combined changes between from and
+// to, "" if there are none.
+func (r *Repository) getDiffPatchId(from, to string) (string, error) {
+	err := validateRefArguments(from, to)
+	if err != nil {
+		return "", err
+	}
+
+	// git diff <from> <to> | git patch-id
+	command := script.LocalCommandFrom("git diff")
+	command.AddAll(from, to, "--")
+	output, err := r.patchIds(command)
+	if err != nil {
+		return "", fmt.Errorf("could not get patch-id for diff %s..%s: %w", from, to, err)
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+// patchIds runs git patch-id on the output of patchCommand. The output is passed
+// via stdin instead of a shell pipeline to stay portable and to keep using the
+// Executor.
+func (r *Repository) patchIds(patchCommand script.Command) (string, error) {
+	pr, err := r.run(patchCommand)
+	if err != nil {
+		return "", err
+	}
+
+	// git patch-id
+	command := script.LocalCommandFrom("git patch-id")
+	pr, err = r.runInvocation(&Invocation{
+		Command: command,
+		Stdin:   strings.NewReader(pr.Output()),
+	})
+	if err != nil {
+		return "", err
+	}
+	return pr.Output(), nil
+}
+
+// IsEffectivelyMergedTo checks if the changes of the branch are contained in
+// target, also detecting rebased and squashed merges.
+func (b *LocalBranch) IsEffectivelyMergedTo(target Branch) (*MergeReport, error) {
+	return isEffectivelyMergedTo(b.repository, b, target)
+}
+
+// IsEffectivelyMergedTo checks if the changes of the branch are contained in
+// target, also detecting rebased and squashed merges.
+func (b *RemoteBranch) IsEffectivelyMergedTo(target Branch) (*MergeReport, error) {
+	return isEffectivelyMergedTo(b.repository, b, target)
+}
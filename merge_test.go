@@ -0,0 +1,88 @@
+package git
+
+import (
+	"context"
+	"testing"
+)
+
+// recordingExecutor runs commands locally and records the binaries.
+type recordingExecutor struct {
+	binaries chan string
+}
+
+func (e *recordingExecutor) Execute(ctx context.Context, invocation *Invocation) (*Result, error) {
+	e.binaries <- invocation.Command.Binary()
+	return NewLocalExecutor().Execute(ctx, invocation)
+}
+
+func TestIsEffectivelyMergedTo(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	writeFile(t, r, "a.txt", "a\n")
+	git("add", "a.txt")
+	git("commit", "--quiet", "-m", "base")
+
+	git("checkout", "--quiet", "-b", "picked")
+	writeFile(t, r, "b.txt", "b\n")
+	git("add", "b.txt")
+	git("commit", "--quiet", "-m", "add b")
+
+	git("checkout", "--quiet", "-b", "squashed", "main")
+	writeFile(t, r, "c.txt", "c\n")
+	git("add", "c.txt")
+	git("commit", "--quiet", "-m", "add c")
+	writeFile(t, r, "c.txt", "c\nc\n")
+	git("commit", "--quiet", "-am", "extend c")
+
+	git("checkout", "--quiet", "-b", "unmerged", "main")
+	writeFile(t, r, "d.txt", "d\n")
+	git("add", "d.txt")
+	git("commit", "--quiet", "-m", "add d")
+
+	git("checkout", "--quiet", "main")
+	writeFile(t, r, "e.txt", "e\n")
+	git("add", "e.txt")
+	git("commit", "--quiet", "-m", "add e")
+	git("cherry-pick", "picked")
+	git("merge", "--quiet", "--squash", "squashed")
+	git("commit", "--quiet", "-m", "squashed c")
+
+	executor := &recordingExecutor{binaries: make(chan string, 1000)}
+	r, err := OpenRepository(r.GetPath(), WithExecutor(executor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	main, err := r.GetBranch("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		branch string
+		merged bool
+		method MergeMethod
+	}{
+		{"picked", true, MergedByPatch},
+		{"squashed", true, MergedBySquash},
+		{"unmerged", false, NotMerged},
+	}
+	for _, test := range tests {
+		branch, err := r.GetBranch(test.branch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report, err := branch.IsEffectivelyMergedTo(main)
+		if err != nil {
+			t.Fatalf("%s: %v", test.branch, err)
+		}
+		if report.Merged != test.merged || report.Method != test.method {
+			t.Errorf("%s: expected merged=%v by %s, got merged=%v by %s", test.branch, test.merged, test.method, report.Merged, report.Method)
+		}
+	}
+
+	close(executor.binaries)
+	for binary := range executor.binaries {
+		if binary != "git" {
+			t.Errorf("expected only git to be run, got %s", binary)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// file modes of tree entries
+const (
+	ModeFile       = "100644"
+	ModeExecutable = "100755"
+	ModeSymlink    = "120000"
+	ModeTree       = "040000"
+	ModeSubmodule  = "160000"
+)
+
+// TreeEntry is an entry of a tree object as listed by git ls-tree.
+type TreeEntry struct {
+	Mode string
+	// Type is "blob", "tree" or "commit", it is derived from Mode if empty
+	Type string
+	Hash string
+	// Name of the entry without directories
+	Name string
+}
+
+// CommitTreeOptions configures Repository.CommitTree.
+type CommitTreeOptions struct {
+	// Tree is the hash of the tree to be committed
+	Tree string
+	// Parents are the hashes of the parent commits, none for a root commit
+	Parents []string
+	Message string
+
+	// AuthorName, AuthorEmail and AuthorDate override the configured identity
+	// and the current time, they are passed via the environment
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	// CommitterName, CommitterEmail and CommitterDate override the configured
+	// identity and the current time, they are passed via the environment
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  time.Time
+
+	Sign SignMode
+	// SigningKey selects the key if Sign is SignEnabled, user.signingKey if empty
+	SigningKey string
+}
+
+// UpdateRefOptions configures Repository.UpdateRef.
+type UpdateRefOptions struct {
+	// OldValue is the hash the ref is expected to point to, it is not checked if empty
+	OldValue string
+	// MustNotExist only creates the ref, failing if it exists already
+	MustNotExist bool
+	// Message for the reflog
+	Message string
+}
+
+// WriteBlob stores content as blob object and returns its hash.
+func (r *Repository) WriteBlob(content []byte) (string, error) {
+	// git hash-object -w --stdin
+	command := script.LocalCommandFrom("git hash-object -w --stdin")
+	pr, err := r.runInvocation(&Invocation{
+		Command: command,
+		Stdin:   bytes.NewReader(content),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not write blob: %w", err)
+	}
+	return pr.TrimmedOutput(), nil
+}
+
+// WriteTree stores a tree object consisting of entries and returns its hash.
+// Subtrees have to be written before.
+func (r *Repository) WriteTree(entries []TreeEntry) (string, error) {
+	// <mode> SP <type> SP <hash> TAB <name> NUL
+	var input bytes.Buffer
+	for _, entry := range entries {
+		if entry.Name == "" || strings.ContainsAny(entry.Name, "/\x00") {
+			return "", fmt.Errorf("invalid tree entry name %q", entry.Name)
+		}
+		entryType := entry.Type
+		if entryType == "" {
+			entryType = typeOfMode(entry.Mode)
+		}
+		fmt.Fprintf(&input, "%s %s %s\t%s\x00", entry.Mode, entryType, entry.Hash, entry.Name)
+	}
+
+	// https://git-scm.com/docs/git-mktree
+	// git mktree -z
+	command := script.LocalCommandFrom("git mktree -z")
+	pr, err := r.runInvocation(&Invocation{
+		Command: command,
+		Stdin:   &input,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not write tree: %w", err)
+	}
+	return pr.TrimmedOutput(), nil
+}
+
+// ListTree returns the entries of a tree, treeish is a tree or a commit. Subtrees
+// are not listed recursively.
+func (r *Repository) ListTree(treeish string) ([]TreeEntry, error) {
+	err := validateRefArguments(treeish)
+	if err != nil {
+		return nil, err
+	}
+
+	// git ls-tree -z <tree-ish>
+	command := script.LocalCommandFrom("git ls-tree -z")
+	command.Add(treeish)
+	pr, err := r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tree %s: %w", treeish, err)
+	}
+
+	entries := make([]TreeEntry, 0)
+	for _, record := range strings.Split(pr.Output(), "\x00") {
+		if record == "" {
+			continue
+		}
+		// <mode> SP <type> SP <hash> TAB <name>
+		parts := strings.SplitN(record, "\t", 2)
+		fields := strings.Fields(parts[0])
+		if len(parts) != 2 || len(fields) != 3 {
+			return nil, fmt.Errorf("invalid line format in tree list: %s", record)
+		}
+		entries = append(entries, TreeEntry{
+			Mode: fields[0],
+			Type: fields[1],
+			Hash: fields[2],
+			Name: parts[1],
+		})
+	}
+	return entries, nil
+}
+
+// CommitTree creates a commit object without updating any ref, the index or
+// the working tree. Use UpdateRef to make it reachable.
+func (r *Repository) CommitTree(opts CommitTreeOptions) (*Commit, error) {
+	err := validateRefArguments(opts.Tree, opts.Parents...)
+	if err != nil {
+		return nil, err
+	}
+
+	// git commit-tree <tree> [-p <parent>]... [-S[<keyid>] | --no-gpg-sign] -F -
+	command := script.LocalCommandFrom("git commit-tree")
+	command.Add(opts.Tree)
+	for _, parent := range opts.Parents {
+		command.AddAll("-p", parent)
+	}
+	switch opts.Sign {
+	case SignDefault:
+	case SignEnabled:
+		command.Add("-S" + opts.SigningKey)
+	case SignDisabled:
+		command.Add("--no-gpg-sign")
+	default:
+		return nil, fmt.Errorf("invalid sign mode %d", opts.Sign)
+	}
+	// the message is read from stdin
+	command.AddAll("-F", "-")
+
+	env := identityEnv("AUTHOR", opts.AuthorName, opts.AuthorEmail, opts.AuthorDate)
+	env = append(env, identityEnv("COMMITTER", opts.CommitterName, opts.CommitterEmail, opts.CommitterDate)...)
+
+	pr, err := r.runInvocation(&Invocation{
+		Command: command,
+		Env:     env,
+		Stdin:   strings.NewReader(opts.Message),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not commit tree %s: %w", opts.Tree, err)
+	}
+	return newCommit(r, pr.TrimmedOutput())
+}
+
+// UpdateRef sets ref like "refs/heads/main" to newValue atomically. If
+// opts.OldValue or opts.MustNotExist is set and the ref does not match,
+// ErrStaleRef is returned.
+func (r *Repository) UpdateRef(ref, newValue string, opts UpdateRefOptions) error {
+	err := validateRefArguments(ref, newValue, opts.OldValue)
+	if err != nil {
+		return err
+	}
+	// refs are passed on stdin, the separators must not be part of them
+	for _, value := range []string{ref, newValue, opts.OldValue} {
+		if strings.ContainsAny(value, " \n") {
+			return fmt.Errorf("%w: %s", ErrInvalidRefName, value)
+		}
+	}
+
+	// https://git-scm.com/docs/git-update-ref
+	// create SP <ref> SP <newvalue> LF
+	// update SP <ref> SP <newvalue> [SP <oldvalue>] LF
+	// an empty old value would require the ref not to exist
+	var input string
+	switch {
+	case opts.MustNotExist:
+		input = fmt.Sprintf("create %s %s\n", ref, newValue)
+	case opts.OldValue != "":
+		input = fmt.Sprintf("update %s %s %s\n", ref, newValue, opts.OldValue)
+	default:
+		input = fmt.Sprintf("update %s %s\n", ref, newValue)
+	}
+
+	// git update-ref [-m <reason>] --stdin
+	command := script.LocalCommandFrom("git update-ref")
+	if opts.Message != "" {
+		command.AddAll("-m", opts.Message)
+	}
+	command.Add("--stdin")
+
+	_, err = r.runInvocation(&Invocation{
+		Command: command,
+		Stdin:   strings.NewReader(input),
+	})
+	var commandErr *GitCommandError
+	if errors.As(err, &commandErr) && isStaleRef(commandErr.Stderr) {
+		return fmt.Errorf("could not update ref %s: %w", ref, ErrStaleRef)
+	}
+	if err != nil {
+		return fmt.Errorf("could not update ref %s: %w", ref, err)
+	}
+	return nil
+}
+
+func typeOfMode(mode string) string {
+	switch mode {
+	case ModeTree:
+		return "tree"
+	case ModeSubmodule:
+		return "commit"
+	}
+	return "blob"
+}
+
+// isStaleRef checks the output of git update-ref for a failed old value check.
+func isStaleRef(stderr string) bool {
+	return strings.Contains(stderr, "but expected") ||
+		strings.Contains(stderr, "reference already exists") ||
+		strings.Contains(stderr, "unable to resolve reference")
+}
@@ -0,0 +1,73 @@
+package git
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommitTreeAndUpdateRef(t *testing.T) {
+	r, git := newTestRepository(t, true)
+
+	blob, err := r.WriteBlob([]byte("content\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subtree, err := r.WriteTree([]TreeEntry{{Mode: ModeFile, Hash: blob, Name: "file.txt"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := r.WriteTree([]TreeEntry{
+		{Mode: ModeExecutable, Hash: blob, Name: "run.sh"},
+		{Mode: ModeTree, Hash: subtree, Name: "dir with spaces"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := r.ListTree(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name != "dir with spaces" || entries[0].Type != "tree" || entries[1].Mode != ModeExecutable {
+		t.Errorf("unexpected tree entries %+v", entries)
+	}
+
+	root, err := r.CommitTree(CommitTreeOptions{Tree: tree, Message: "root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := r.CommitTree(CommitTreeOptions{Tree: subtree, Parents: []string{root.GetHash()}, Message: "child"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := r.CommitTree(CommitTreeOptions{Tree: subtree, Message: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const ref = "refs/heads/main"
+	tests := []struct {
+		name      string
+		newValue  string
+		opts      UpdateRefOptions
+		expectErr error
+		expected  string
+	}{
+		{"create", root.GetHash(), UpdateRefOptions{MustNotExist: true}, nil, root.GetHash()},
+		{"create existing", child.GetHash(), UpdateRefOptions{MustNotExist: true}, ErrStaleRef, root.GetHash()},
+		{"checked update", child.GetHash(), UpdateRefOptions{OldValue: root.GetHash(), Message: "bot"}, nil, child.GetHash()},
+		{"stale checked update", other.GetHash(), UpdateRefOptions{OldValue: root.GetHash()}, ErrStaleRef, child.GetHash()},
+		{"unchecked update", other.GetHash(), UpdateRefOptions{}, nil, other.GetHash()},
+	}
+	for _, test := range tests {
+		err := r.UpdateRef(ref, test.newValue, test.opts)
+		if test.expectErr == nil && err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if test.expectErr != nil && !errors.Is(err, test.expectErr) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expectErr, err)
+		}
+		if actual := git("rev-parse", ref); actual != test.expected {
+			t.Errorf("%s: expected %s at %s, got %s", test.name, ref, test.expected, actual)
+		}
+	}
+}
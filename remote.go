@@ -32,12 +32,7 @@ func (r *Remote) HasBranch(name string) (bool, error) {
 	command := script.LocalCommandFrom("git show-ref --verify --quiet")
 	command.Add("refs/remotes/" + r.GetName() + "/" + name)
 
-	pr, err := r.repository.Execute(command)
-	if err != nil {
-		return false, err
-	}
-
-	return pr.Successful(), nil
+	return r.repository.refExists(command)
 }
 
 func (r *Remote) GetBranch(name string) (*RemoteBranch, error) {
@@ -46,7 +41,7 @@ func (r *Remote) GetBranch(name string) (*RemoteBranch, error) {
 		return nil, err
 	}
 	if !existing {
-		return nil, fmt.Errorf("could not find RemoteBranch %s", name)
+		return nil, fmt.Errorf("%w: %s/%s", ErrBranchNotFound, r.GetName(), name)
 	}
 	return newRemoteBranch(r.repository, r, name), nil
 }
@@ -56,12 +51,9 @@ func (r *Remote) GetBranches() ([]*RemoteBranch, error) {
 	command := script.LocalCommandFrom("git ls-remote --heads")
 	command.Add(r.GetName())
 
-	pr, err := r.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not list remote branches on %s", r.GetName())
-	}
+	pr, err := r.repository.run(command)
 	if err != nil {
-		return []*RemoteBranch{}, err
+		return []*RemoteBranch{}, fmt.Errorf("could not list remote branches on %s: %w", r.GetName(), err)
 	}
 
 	branches := make([]*RemoteBranch, 0, 10)
@@ -87,7 +79,7 @@ func (r *Remote) GetMainBranch() (*RemoteBranch, error) {
 	candidates := make([]string, 0, 3)
 
 	// check config
-	command := script.LocalCommandFrom("git config init.defaultLocalBranch")
+	command := script.LocalCommandFrom("git config init.defaultBranch")
 	pr, err := r.repository.Execute(command)
 	if err != nil {
 		return nil, err
@@ -115,7 +107,27 @@ func (r *Remote) GetMainBranch() (*RemoteBranch, error) {
 		return newRemoteBranch(r.repository, r, candidate), nil
 	}
 
-	return nil, fmt.Errorf("no main branch found")
+	return nil, ErrNoMainBranch
+}
+
+// getDefaultBranchName returns the branch refs/remotes/<remote>/HEAD points to,
+// "" if it is not set (e.g. if the remote was added without cloning).
+func (r *Remote) getDefaultBranchName() (string, error) {
+	// git symbolic-ref --quiet refs/remotes/<remote>/HEAD
+	command := script.LocalCommandFrom("git symbolic-ref --quiet")
+	command.Add("refs/remotes/" + r.GetName() + "/HEAD")
+	pr, err := r.repository.Execute(command)
+	if err != nil {
+		return "", err
+	}
+	// exit code 1 means it is not a symbolic ref or does not exist
+	if pr.ExitCode() == 1 {
+		return "", nil
+	}
+	if !pr.Successful() {
+		return "", fmt.Errorf("could not get default branch of remote %s: %w", r.GetName(), newGitCommandError(command, pr))
+	}
+	return strings.TrimPrefix(pr.TrimmedOutput(), "refs/remotes/"+r.GetName()+"/"), nil
 }
 
 func (r *Remote) String() string {
@@ -12,6 +12,9 @@ type RemoteBranch struct {
 
 	repository *Repository
 	remote     *Remote
+
+	// cached values
+	info *BranchInfo
 }
 
 func newRemoteBranch(repository *Repository, remote *Remote, name string) *RemoteBranch {
@@ -27,6 +30,10 @@ func (b *RemoteBranch) GetName() string {
 	return b.name
 }
 
+func (b *RemoteBranch) GetRemote() *Remote {
+	return b.remote
+}
+
 func (b *RemoteBranch) GetFullName() string {
 	return b.remote.GetName() + "/" + b.name
 }
@@ -36,54 +43,39 @@ func (b *RemoteBranch) Delete() error {
 	command := script.LocalCommandFrom("git push")
 	command.AddAll(b.remote.GetName(), "--delete", b.GetName())
 
-	pr, err := b.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not delete remote branch %s on %s", b.GetName(), b.remote)
+	_, err := b.repository.run(command)
+	if err != nil {
+		return fmt.Errorf("could not delete remote branch %s on %s: %w", b.GetName(), b.remote.GetName(), err)
 	}
-	return err
+	return nil
 }
 
-func (b *RemoteBranch) IsMergedTo(target *RemoteBranch) (bool, error) {
-	localBranchHead, err := b.GetHeadCommit()
-	if err != nil {
-		return false, err
-	}
-	targetHead, err := target.GetHeadCommit()
+func (b *RemoteBranch) IsMainBranch() (bool, error) {
+	main, err := b.remote.GetMainBranch()
 	if err != nil {
 		return false, err
 	}
+	return main.Equals(b), nil
+}
 
-	// both have the same HEAD commit? -> merged by definition
-	if localBranchHead.Equals(targetHead) {
-		return true, nil
-	}
-
-	// https://stackoverflow.com/a/40011122
-	// git merge-base <commit-hash-step1> <commit-hash-step2>
-	command := script.LocalCommandFrom("git merge-base")
-	command.AddAll(localBranchHead.GetHash(), targetHead.GetHash())
-
-	pr, err := b.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not find merge-base between %s and %s", localBranchHead.GetHash(), targetHead.GetHash())
-	}
-	if err != nil {
-		return false, err
-	}
-	mergeBase := strings.TrimSpace(pr.Output())
-	return mergeBase == localBranchHead.GetHash(), nil
+func (b *RemoteBranch) IsMergedTo(target Branch) (bool, error) {
+	return isMergedTo(b.repository, b, target)
 }
 
+// GetHeadCommit returns the commit the branch points to. For branches with
+// metadata like those retrieved by Remote.ListBranches, it is taken from the
+// snapshot.
 func (r *RemoteBranch) GetHeadCommit() (*Commit, error) {
+	if r.info != nil {
+		return newCommit(r.repository, r.info.HeadHash)
+	}
+
 	command := script.LocalCommandFrom("git rev-parse")
 	command.Add(r.GetFullName())
 
-	pr, err := r.repository.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("getting HEAD commit faile for RemoteBranch %s", r.GetName())
-	}
+	pr, err := r.repository.run(command)
 	if err != nil {
-		return &Commit{}, err
+		return &Commit{}, fmt.Errorf("could not get HEAD commit for remote branch %s: %w", r.GetFullName(), err)
 	}
 
 	hash := strings.TrimSpace(pr.Output())
@@ -91,6 +83,24 @@ func (r *RemoteBranch) GetHeadCommit() (*Commit, error) {
 	return newCommit(r.repository, hash)
 }
 
+// GetCommitsByMessage returns the non-merge commits on the branch whose message
+// contains msg literally.
+func (b *RemoteBranch) GetCommitsByMessage(msg string) ([]*Commit, error) {
+	return b.SearchCommits(SearchOptions{
+		Patterns:  []string{msg},
+		MatchMode: MatchFixed,
+	})
+}
+
+// SearchCommits returns the commits on the branch whose message matches.
+func (b *RemoteBranch) SearchCommits(opts SearchOptions) ([]*Commit, error) {
+	result, err := searchCommits(b.repository, "refs/remotes/"+b.GetFullName(), opts)
+	if err != nil {
+		return []*Commit{}, fmt.Errorf("could not search commits on branch %s: %w", b.GetFullName(), err)
+	}
+	return result, nil
+}
+
 func (b *RemoteBranch) Equals(otherRemoteBranch *RemoteBranch) bool {
 	return b.GetFullName() == otherRemoteBranch.GetFullName()
 }
@@ -1,70 +1,222 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/Masterminds/semver"
 	script "github.com/jojomi/go-script/v2"
 )
 
 type Repository struct {
-	path string
+	path      string
+	gitDir    string
+	commonDir string
+	bare      bool
+	executor  Executor
+	ctx       context.Context
 
 	// cached values
 	mainLocalBranchName string
+	versionCache        *gitVersionCache
 }
 
-func OpenRepository(path string) (*Repository, error) {
+// RepositoryOption configures a Repository upon opening.
+type RepositoryOption func(r *Repository)
+
+// WithExecutor sets the Executor used to run git commands for the Repository.
+func WithExecutor(executor Executor) RepositoryOption {
+	return func(r *Repository) {
+		r.executor = executor
+	}
+}
+
+// WithContext binds the git commands of the Repository to ctx, including the ones
+// run while opening, initializing or cloning it. See also Repository.WithContext.
+func WithContext(ctx context.Context) RepositoryOption {
+	return func(r *Repository) {
+		r.ctx = ctx
+	}
+}
+
+// OpenRepository opens the repository at path, which must be a bare repository
+// or be located inside a work tree. The path of the returned Repository is the
+// top-level directory of the work tree.
+func OpenRepository(path string, options ...RepositoryOption) (*Repository, error) {
 	r := Repository{
-		path: path,
+		path:         path,
+		executor:     NewLocalExecutor(),
+		versionCache: &gitVersionCache{},
+	}
+	for _, option := range options {
+		option(&r)
+	}
+
+	err := r.discover()
+	if err != nil {
+		return nil, fmt.Errorf("could not open repository at %s: %w", path, err)
 	}
 	return &r, nil
 }
 
+// DiscoverRepository opens the repository containing path. Other than
+// OpenRepository, path may also denote a file or a not yet existing location, the
+// nearest existing parent directory is used then.
+func DiscoverRepository(path string, options ...RepositoryOption) (*Repository, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		info, err := os.Stat(dir)
+		if err == nil && info.IsDir() {
+			break
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("%w: %s", ErrNotARepository, path)
+		}
+		dir = parent
+	}
+
+	return OpenRepository(dir, options...)
+}
+
+// discover validates the repository path and resolves the git directories.
+func (r *Repository) discover() error {
+	// https://git-scm.com/docs/git-rev-parse
+	// git rev-parse --is-bare-repository --is-inside-work-tree --absolute-git-dir --git-common-dir
+	command := script.LocalCommandFrom("git rev-parse --is-bare-repository --is-inside-work-tree --absolute-git-dir --git-common-dir")
+	pr, err := r.run(command)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(pr.TrimmedOutput(), "\n")
+	if len(lines) != 4 {
+		return fmt.Errorf("unexpected output of %s: %s", command, pr.Output())
+	}
+	r.bare = lines[0] == "true"
+	insideWorkTree := lines[1] == "true"
+	r.gitDir = lines[2]
+	r.commonDir = lines[3]
+	// the common dir may be reported relative to the working directory
+	if !filepath.IsAbs(r.commonDir) {
+		r.commonDir, err = filepath.Abs(filepath.Join(r.path, r.commonDir))
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.bare {
+		r.path = r.gitDir
+		return nil
+	}
+	if !insideWorkTree {
+		return fmt.Errorf("%w: not inside a work tree", ErrNotARepository)
+	}
+
+	// git rev-parse --show-toplevel
+	command = script.LocalCommandFrom("git rev-parse --show-toplevel")
+	pr, err = r.run(command)
+	if err != nil {
+		return err
+	}
+	r.path = pr.TrimmedOutput()
+	return nil
+}
+
+// WithContext returns a shallow copy of the Repository whose git commands are
+// bound to ctx. Remotes, branches and commits retrieved from the copy use ctx as
+// well, the running git process is killed when ctx is done.
+func (r *Repository) WithContext(ctx context.Context) *Repository {
+	if ctx == nil {
+		panic("nil context")
+	}
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
+}
+
+// Context returns the context of the Repository, context.Background() if none was set.
+func (r *Repository) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
+// GetPath returns the top-level directory of the work tree, or the git
+// directory for bare repositories.
 func (r *Repository) GetPath() string {
 	return r.path
 }
 
+// GetGitDir returns the absolute path of the git directory, e.g. <path>/.git.
+func (r *Repository) GetGitDir() string {
+	return r.gitDir
+}
+
+// GetCommonDir returns the absolute path of the git directory shared by all
+// linked work trees. It differs from GetGitDir only for linked work trees.
+func (r *Repository) GetCommonDir() string {
+	return r.commonDir
+}
+
+// IsBare returns true iff the repository has no work tree.
+func (r *Repository) IsBare() bool {
+	return r.bare
+}
+
 func (r *Repository) GetRemote(name string) (*Remote, error) {
 	existing, err := r.HasRemote(name)
 	if err != nil {
 		return nil, err
 	}
 	if !existing {
-		return nil, fmt.Errorf("could not find remote %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrRemoteNotFound, name)
 	}
 	return newRemote(r, name), nil
 }
 
 func (r *Repository) GetRemotes() ([]*Remote, error) {
-	command := script.LocalCommandFrom("git remote show")
-	pr, err := r.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not list remotes")
-	}
+	command := script.LocalCommandFrom("git remote")
+	pr, err := r.run(command)
 	if err != nil {
-		return []*Remote{}, err
+		return []*Remote{}, fmt.Errorf("could not list remotes: %w", err)
 	}
 
 	remotes := make([]*Remote, 0, 10)
 	for _, line := range strings.Split(strings.TrimSpace(pr.Output()), "\n") {
+		if line == "" {
+			continue
+		}
 		remotes = append(remotes, newRemote(r, line))
 	}
 
-	return remotes, err
+	return remotes, nil
 }
 
 func (r *Repository) HasRemote(name string) (bool, error) {
-	command := script.LocalCommandFrom("git remote show")
-	command.Add(name)
-
-	pr, err := r.Execute(command)
+	// git remote show <name> would contact the remote, so check the list instead
+	remotes, err := r.GetRemotes()
 	if err != nil {
 		return false, err
 	}
 
-	return pr.Successful(), nil
+	for _, remote := range remotes {
+		if remote.GetName() == name {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (r *Repository) GetBranch(name string) (*LocalBranch, error) {
@@ -73,35 +225,18 @@ func (r *Repository) GetBranch(name string) (*LocalBranch, error) {
 		return nil, err
 	}
 	if !existing {
-		return nil, fmt.Errorf("could not find local branch %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrBranchNotFound, name)
 	}
 	return newLocalBranch(r, name), nil
 }
 
+// GetBranches lists all local branches sorted by name, see ListBranches.
 func (r *Repository) GetBranches() ([]*LocalBranch, error) {
-	// https://stackoverflow.com/a/66190381
-	// git show-ref --heads
-	command := script.LocalCommandFrom("git show-ref --heads")
-	pr, err := r.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("could not list local LocalBranches")
-	}
-	if err != nil {
-		return []*LocalBranch{}, err
-	}
-
-	localBranches := make([]*LocalBranch, 0, 10)
-
-	branchList, err := parseBranchList(pr.Output())
+	branches, err := r.ListBranches(BranchListOptions{})
 	if err != nil {
-		return nil, err
-	}
-
-	for _, branch := range branchList {
-		localBranches = append(localBranches, newLocalBranch(r, branch))
+		return []*LocalBranch{}, fmt.Errorf("could not list local branches: %w", err)
 	}
-
-	return localBranches, err
+	return branches, nil
 }
 
 func (r *Repository) String() string {
@@ -114,12 +249,23 @@ func (r *Repository) HasBranch(name string) (bool, error) {
 	command := script.LocalCommandFrom("git show-ref --verify --quiet")
 	command.Add("refs/heads/" + name)
 
+	return r.refExists(command)
+}
+
+// refExists interprets the result of a git show-ref --verify --quiet command.
+func (r *Repository) refExists(command script.Command) (bool, error) {
 	pr, err := r.Execute(command)
 	if err != nil {
 		return false, err
 	}
-
-	return pr.Successful(), nil
+	// exit code 1 means the ref does not exist, anything else is an actual error
+	if pr.ExitCode() == 1 {
+		return false, nil
+	}
+	if !pr.Successful() {
+		return false, newGitCommandError(command, pr)
+	}
+	return true, nil
 }
 
 func (r *Repository) GetMainBranch() (*LocalBranch, error) {
@@ -131,7 +277,7 @@ func (r *Repository) GetMainBranch() (*LocalBranch, error) {
 	candidates := make([]string, 0, 3)
 
 	// check config
-	command := script.LocalCommandFrom("git config init.defaultLocalBranch")
+	command := script.LocalCommandFrom("git config init.defaultBranch")
 	pr, err := r.Execute(command)
 	if err != nil {
 		return nil, err
@@ -159,14 +305,13 @@ func (r *Repository) GetMainBranch() (*LocalBranch, error) {
 		return newLocalBranch(r, candidate), nil
 	}
 
-	return nil, fmt.Errorf("no main branch found")
+	return nil, ErrNoMainBranch
 }
 
 func (r *Repository) GetCurrentBranch() (*LocalBranch, error) {
 	// https://stackoverflow.com/a/6245587
 
-	v := MustGetGitVersion()
-	min, err := semver.NewConstraint(">= 2.22")
+	supportsShowCurrent, err := r.checkGitVersion(">= 2.22")
 	if err != nil {
 		return &LocalBranch{}, err
 	}
@@ -176,35 +321,95 @@ func (r *Repository) GetCurrentBranch() (*LocalBranch, error) {
 	// git LocalBranch --show-current
 	command = script.LocalCommandFrom("git branch --show-current")
 
-	if !min.Check(v) {
+	if !supportsShowCurrent {
 		// Fallback
 		// git rev-parse --abbrev-ref HEAD
 		command = script.LocalCommandFrom("git rev-parse --abbrev-ref HEAD")
 	}
 
 	// common parsing due to same output structure
-	pr, err := r.Execute(command)
-	if !pr.Successful() {
-		err = fmt.Errorf("failed getting current LocalBranch")
-	}
+	pr, err := r.run(command)
 	if err != nil {
-		return &LocalBranch{}, err
+		return &LocalBranch{}, fmt.Errorf("could not get current branch: %w", err)
 	}
-	return newLocalBranch(r, strings.TrimSpace(pr.Output())), nil
+	name := strings.TrimSpace(pr.Output())
+	// empty or "HEAD" if there is no current branch
+	if name == "" || name == "HEAD" {
+		return &LocalBranch{}, ErrDetachedHead
+	}
+	return newLocalBranch(r, name), nil
 }
 
-func (r *Repository) GetCurrentCommit() (Commit, error) {
-	return Commit{}, nil
+// GetCurrentCommit returns the commit HEAD points to. ErrUnbornBranch is returned
+// if there are no commits on the current branch yet.
+func (r *Repository) GetCurrentCommit() (*Commit, error) {
+	commit, err := r.resolveHeadCommit()
+	if err != nil {
+		return &Commit{}, err
+	}
+	if commit == nil {
+		return &Commit{}, ErrUnbornBranch
+	}
+	return commit, nil
 }
 
-var sc = script.NewContext()
+func (r *Repository) Execute(c script.Command) (*Result, error) {
+	return r.invoke(r.Context(), &Invocation{
+		Command: c,
+	})
+}
 
-func (r *Repository) Execute(c script.Command) (pr *script.ProcessResult, err error) {
+// invoke hands the invocation to the executor of the Repository, running it in
+// the repository path.
+func (r *Repository) invoke(ctx context.Context, invocation *Invocation) (*Result, error) {
 	workingDir := r.GetPath()
 	if workingDir == "" {
-		return nil, fmt.Errorf("repository path not set")
+		return nil, ErrRepositoryPathUnset
+	}
+	executor := r.executor
+	if executor == nil {
+		executor = NewLocalExecutor()
 	}
-	sc.SetWorkingDir(workingDir)
 
-	return sc.ExecuteSilent(c)
+	invocation.Dir = workingDir
+	return executor.Execute(ctx, invocation)
+}
+
+// run executes a command like Execute, but reports unsuccessful executions as
+// *GitCommandError.
+func (r *Repository) run(command script.Command) (*Result, error) {
+	return r.runInvocation(&Invocation{
+		Command: command,
+	})
+}
+
+// runInvocation is like run, allowing to set further details of the invocation.
+func (r *Repository) runInvocation(invocation *Invocation) (*Result, error) {
+	pr, err := r.invoke(r.Context(), invocation)
+	if err != nil {
+		return pr, err
+	}
+	if !pr.Successful() {
+		return pr, newGitCommandError(invocation.Command, pr)
+	}
+	return pr, nil
+}
+
+// getConfigValue returns the value of a config key, "" if it is not set.
+func (r *Repository) getConfigValue(key string) (string, error) {
+	// git config --get <key>
+	command := script.LocalCommandFrom("git config --get")
+	command.Add(key)
+	pr, err := r.Execute(command)
+	if err != nil {
+		return "", err
+	}
+	// exit code 1 means the key is not set
+	if pr.ExitCode() == 1 {
+		return "", nil
+	}
+	if !pr.Successful() {
+		return "", fmt.Errorf("could not get config value %s: %w", key, newGitCommandError(command, pr))
+	}
+	return pr.TrimmedOutput(), nil
 }
@@ -0,0 +1,148 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// InitOptions configures InitRepository.
+type InitOptions struct {
+	Bare          bool
+	InitialBranch string
+	TemplateDir   string
+}
+
+// CloneOptions configures CloneRepository.
+type CloneOptions struct {
+	// Branch to check out instead of the remote's HEAD, may also be a tag
+	Branch string
+	// Depth creates a shallow clone with the given number of commits if > 0
+	Depth        int
+	SingleBranch bool
+	Bare         bool
+	// Mirror implies Bare and maps all refs of the remote
+	Mirror            bool
+	RecurseSubmodules bool
+	// Filter enables partial clone, e.g. "blob:none"
+	Filter string
+}
+
+// InitRepository creates a new repository at path (like git init) and opens it.
+// Initializing an existing repository is safe and does not overwrite anything.
+func InitRepository(path string, opts InitOptions, options ...RepositoryOption) (*Repository, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newCreatorRepository(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	supportsInitialBranch, err := r.checkGitVersion(">= 2.28")
+	if err != nil {
+		return nil, err
+	}
+
+	// git init [--bare] [--template=<dir>] [--initial-branch=<name>] -- <path>
+	command := script.LocalCommandFrom("git init --quiet")
+	if opts.Bare {
+		command.Add("--bare")
+	}
+	if opts.TemplateDir != "" {
+		command.Add("--template=" + opts.TemplateDir)
+	}
+	if opts.InitialBranch != "" && supportsInitialBranch {
+		command.Add("--initial-branch=" + opts.InitialBranch)
+	}
+	command.AddAll("--", absPath)
+
+	_, err = r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not init repository at %s: %w", absPath, err)
+	}
+
+	repository, err := OpenRepository(absPath, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.InitialBranch != "" && !supportsInitialBranch {
+		// Fallback
+		// git symbolic-ref HEAD refs/heads/<name>
+		command = script.LocalCommandFrom("git symbolic-ref HEAD")
+		command.Add("refs/heads/" + opts.InitialBranch)
+		_, err = repository.run(command)
+		if err != nil {
+			return nil, fmt.Errorf("could not set initial branch %s: %w", opts.InitialBranch, err)
+		}
+	}
+
+	return repository, nil
+}
+
+// CloneRepository clones the repository at url to path (like git clone) and opens it.
+func CloneRepository(url, path string, opts CloneOptions, options ...RepositoryOption) (*Repository, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newCreatorRepository(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// git clone [<options>] -- <url> <path>
+	command := script.LocalCommandFrom("git clone --quiet")
+	if opts.Branch != "" {
+		command.Add("--branch=" + opts.Branch)
+	}
+	if opts.Depth > 0 {
+		command.Add("--depth=" + strconv.Itoa(opts.Depth))
+	}
+	if opts.SingleBranch {
+		command.Add("--single-branch")
+	}
+	if opts.Bare {
+		command.Add("--bare")
+	}
+	if opts.Mirror {
+		command.Add("--mirror")
+	}
+	if opts.RecurseSubmodules {
+		command.Add("--recurse-submodules")
+	}
+	if opts.Filter != "" {
+		command.Add("--filter=" + opts.Filter)
+	}
+	command.AddAll("--", url, absPath)
+
+	_, err = r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not clone %s to %s: %w", redactURL(url), absPath, err)
+	}
+
+	return OpenRepository(absPath, options...)
+}
+
+// newCreatorRepository returns a Repository not yet backed by an actual
+// repository, used to run git commands that create one.
+func newCreatorRepository(options ...RepositoryOption) (*Repository, error) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	r := Repository{
+		path:         workingDir,
+		executor:     NewLocalExecutor(),
+		versionCache: &gitVersionCache{},
+	}
+	for _, option := range options {
+		option(&r)
+	}
+	return &r, nil
+}
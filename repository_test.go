@@ -0,0 +1,113 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeExecutor answers commands with canned results without running git.
+type fakeExecutor struct {
+	results map[string]*Result
+	calls   int64
+}
+
+func (e *fakeExecutor) Execute(ctx context.Context, invocation *Invocation) (*Result, error) {
+	atomic.AddInt64(&e.calls, 1)
+	command := strings.Join(append([]string{invocation.Command.Binary()}, invocation.Command.Args()...), " ")
+	result, ok := e.results[command]
+	if !ok {
+		return nil, fmt.Errorf("unexpected command %s", command)
+	}
+	return result, nil
+}
+
+func newFakeRepositoryExecutor(path, branch, version string) *fakeExecutor {
+	return &fakeExecutor{
+		results: map[string]*Result{
+			"git rev-parse --is-bare-repository --is-inside-work-tree --absolute-git-dir --git-common-dir": NewResult(
+				"false\ntrue\n"+path+"/.git\n"+path+"/.git\n", "", 0),
+			"git rev-parse --show-toplevel":   NewResult(path+"\n", "", 0),
+			"git version":                     NewResult("git version "+version+"\n", "", 0),
+			"git branch --show-current":       NewResult(branch+"\n", "", 0),
+			"git rev-parse --abbrev-ref HEAD": NewResult(branch+"\n", "", 0),
+		},
+	}
+}
+
+func TestConcurrentRepositoriesWithFakeExecutor(t *testing.T) {
+	branches := []string{"main", "develop"}
+	repositories := make([]*Repository, len(branches))
+	for i, branch := range branches {
+		path := "/repositories/" + branch
+		r, err := OpenRepository(path, WithExecutor(newFakeRepositoryExecutor(path, branch, "2.39.2")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.GetPath() != path {
+			t.Fatalf("expected path %s, got %s", path, r.GetPath())
+		}
+		repositories[i] = r
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 50; i++ {
+		for j, r := range repositories {
+			wg.Add(1)
+			go func(r *Repository, expected string) {
+				defer wg.Done()
+				branch, err := r.GetCurrentBranch()
+				if err != nil {
+					errs <- err
+					return
+				}
+				if branch.GetName() != expected {
+					errs <- fmt.Errorf("expected branch %s, got %s", expected, branch.GetName())
+				}
+			}(r, branches[j])
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestGitVersionFromExecutor(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"2.39.2", "git branch --show-current"},
+		{"2.39.2.windows.1", "git branch --show-current"},
+		{"2.21.0 (Apple Git-122)", "git rev-parse --abbrev-ref HEAD"},
+	}
+	for _, test := range tests {
+		executor := newFakeRepositoryExecutor("/repository", "main", test.version)
+		// only one of the commands is allowed
+		for _, command := range []string{"git branch --show-current", "git rev-parse --abbrev-ref HEAD"} {
+			if command != test.expected {
+				delete(executor.results, command)
+			}
+		}
+		r, err := OpenRepository("/repository", WithExecutor(executor))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			_, err = r.GetCurrentBranch()
+			if err != nil {
+				t.Errorf("version %s: %v", test.version, err)
+			}
+		}
+		// 2 for opening, 1 for the version (cached), 2 for the branch
+		if calls := atomic.LoadInt64(&executor.calls); calls != 5 {
+			t.Errorf("version %s: expected 5 commands, got %d", test.version, calls)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"fmt"
+)
+
+// SearchOptions configures searching commits on a branch by their message.
+type SearchOptions struct {
+	Patterns  []string
+	MatchMode MatchMode
+	// IgnoreCase matches patterns case-insensitively
+	IgnoreCase bool
+	// AllMatch requires all patterns to match instead of any of them
+	AllMatch      bool
+	IncludeMerges bool
+}
+
+// searchCommits lists the commits reachable from revision whose message matches.
+func searchCommits(repository *Repository, revision string, opts SearchOptions) ([]*Commit, error) {
+	if len(opts.Patterns) == 0 {
+		return nil, fmt.Errorf("no search pattern given")
+	}
+
+	it, err := repository.Log(LogOptions{
+		Revisions:  []string{revision},
+		NoMerges:   !opts.IncludeMerges,
+		Grep:       opts.Patterns,
+		MatchMode:  opts.MatchMode,
+		IgnoreCase: opts.IgnoreCase,
+		AllMatch:   opts.AllMatch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return it.All()
+}
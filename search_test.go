@@ -0,0 +1,96 @@
+package git
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSearchCommits(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	git("commit", "--quiet", "--allow-empty", "-m", "init")
+	git("checkout", "--quiet", "-b", "side")
+	git("commit", "--quiet", "--allow-empty", "-m", "side work")
+	git("checkout", "--quiet", "main")
+	for _, message := range []string{"fix [JIRA-1] crash", "bump version to 1.2", "version 1x2", "add a*b support", "Fix typo"} {
+		git("commit", "--quiet", "--allow-empty", "-m", message)
+	}
+	git("merge", "--quiet", "--no-ff", "-m", "Merge fix [JIRA-2]", "side")
+	git("remote", "add", "origin", "https://example.com/repo.git")
+	git("update-ref", "refs/remotes/origin/main", "main")
+
+	localBranch, err := r.GetBranch("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := r.GetRemote("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteBranch, err := remote.GetBranch("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		opts     SearchOptions
+		expected []string
+	}{
+		{SearchOptions{Patterns: []string{"[JIRA-1]"}}, []string{"fix [JIRA-1] crash"}},
+		{SearchOptions{Patterns: []string{"1.2"}}, []string{"bump version to 1.2"}},
+		{SearchOptions{Patterns: []string{"a*b"}}, []string{"add a*b support"}},
+		{SearchOptions{Patterns: []string{"1.2"}, MatchMode: MatchExtendedRegexp}, []string{"bump version to 1.2", "version 1x2"}},
+		{SearchOptions{Patterns: []string{"fix"}}, []string{"fix [JIRA-1] crash"}},
+		{SearchOptions{Patterns: []string{"fix"}, IgnoreCase: true}, []string{"Fix typo", "fix [JIRA-1] crash"}},
+		{SearchOptions{Patterns: []string{"fix"}, IgnoreCase: true, IncludeMerges: true}, []string{"Fix typo", "Merge fix [JIRA-2]", "fix [JIRA-1] crash"}},
+		{SearchOptions{Patterns: []string{"typo", "crash"}}, []string{"Fix typo", "fix [JIRA-1] crash"}},
+		{SearchOptions{Patterns: []string{"fix", "crash"}, AllMatch: true}, []string{"fix [JIRA-1] crash"}},
+		{SearchOptions{Patterns: []string{"[JIRA-2]"}}, []string{}},
+	}
+	for _, branch := range []interface {
+		GetFullName() string
+		SearchCommits(opts SearchOptions) ([]*Commit, error)
+	}{localBranch, remoteBranch} {
+		for _, test := range tests {
+			commits, err := branch.SearchCommits(test.opts)
+			if err != nil {
+				t.Errorf("%s %+v: unexpected error %v", branch.GetFullName(), test.opts, err)
+				continue
+			}
+			if actual := commitSubjects(t, commits); !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("%s %+v: expected %v, got %v", branch.GetFullName(), test.opts, test.expected, actual)
+			}
+		}
+
+		_, err = branch.SearchCommits(SearchOptions{})
+		if err == nil {
+			t.Errorf("%s: expected error without patterns", branch.GetFullName())
+		}
+	}
+
+	// patterns are matched literally
+	for _, search := range []func(string) ([]*Commit, error){localBranch.GetCommitsByMessage, remoteBranch.GetCommitsByMessage} {
+		commits, err := search("a*b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := commitSubjects(t, commits); !reflect.DeepEqual(actual, []string{"add a*b support"}) {
+			t.Errorf("expected literal match, got %v", actual)
+		}
+	}
+}
+
+// commitSubjects returns the sorted subjects of commits.
+func commitSubjects(t *testing.T, commits []*Commit) []string {
+	t.Helper()
+	subjects := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		info, err := commit.GetInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		subjects = append(subjects, info.Subject)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
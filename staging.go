@@ -0,0 +1,219 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// AddOptions configures Repository.Add.
+type AddOptions struct {
+	// All stages deletions too, the whole working tree if no paths are given
+	All bool
+	// IntentToAdd records untracked paths without their content
+	IntentToAdd bool
+	// Force adds ignored files
+	Force bool
+}
+
+// RemoveOptions configures Repository.Remove.
+type RemoveOptions struct {
+	// Cached removes the paths from the index only, keeping the files
+	Cached bool
+	// Force removes files with local modifications
+	Force bool
+	// Recursive removes directories
+	Recursive bool
+}
+
+// ResetMode defines what is reset by Repository.Reset.
+type ResetMode int
+
+const (
+	// ResetMixed resets the index but not the working tree
+	ResetMixed ResetMode = iota
+	// ResetSoft keeps the index and the working tree
+	ResetSoft
+	// ResetHard discards all changes in the index and the working tree
+	ResetHard
+)
+
+func (m ResetMode) flag() (string, error) {
+	switch m {
+	case ResetMixed:
+		return "--mixed", nil
+	case ResetSoft:
+		return "--soft", nil
+	case ResetHard:
+		return "--hard", nil
+	}
+	return "", fmt.Errorf("invalid reset mode %d", m)
+}
+
+// RestoreOptions configures Repository.Restore. Only the working tree is
+// restored if neither Staged nor Worktree is set.
+type RestoreOptions struct {
+	// Source revision, the index for the working tree and HEAD for the index if empty
+	Source   string
+	Staged   bool
+	Worktree bool
+}
+
+// Add stages the paths or pathspecs. Paths are always separated from options.
+func (r *Repository) Add(paths []string, opts AddOptions) error {
+	if len(paths) == 0 && !opts.All {
+		return ErrNoPaths
+	}
+
+	// git add [--all] [--intent-to-add] [--force] -- <paths>
+	command := script.LocalCommandFrom("git add")
+	if opts.All {
+		command.Add("--all")
+	}
+	if opts.IntentToAdd {
+		command.Add("--intent-to-add")
+	}
+	if opts.Force {
+		command.Add("--force")
+	}
+	command.Add("--")
+	command.AddAll(paths...)
+
+	_, err := r.run(command)
+	if err != nil {
+		return fmt.Errorf("could not add %s: %w", strings.Join(paths, ", "), err)
+	}
+	return nil
+}
+
+// Remove removes the paths from the index and the working tree.
+func (r *Repository) Remove(paths []string, opts RemoveOptions) error {
+	if len(paths) == 0 {
+		return ErrNoPaths
+	}
+
+	// git rm [--cached] [--force] [-r] -- <paths>
+	command := script.LocalCommandFrom("git rm --quiet")
+	if opts.Cached {
+		command.Add("--cached")
+	}
+	if opts.Force {
+		command.Add("--force")
+	}
+	if opts.Recursive {
+		command.Add("-r")
+	}
+	command.Add("--")
+	command.AddAll(paths...)
+
+	_, err := r.run(command)
+	if err != nil {
+		return fmt.Errorf("could not remove %s: %w", strings.Join(paths, ", "), err)
+	}
+	return nil
+}
+
+// Reset sets the current branch to revision (HEAD if empty), mode defines
+// whether the index and the working tree are reset too.
+func (r *Repository) Reset(revision string, mode ResetMode) error {
+	if revision == "" {
+		revision = "HEAD"
+	}
+	err := validateRefArguments(revision)
+	if err != nil {
+		return err
+	}
+	flag, err := mode.flag()
+	if err != nil {
+		return err
+	}
+
+	// git reset --soft|--mixed|--hard <revision> --
+	command := script.LocalCommandFrom("git reset --quiet")
+	command.AddAll(flag, revision, "--")
+
+	_, err = r.run(command)
+	if err != nil {
+		return fmt.Errorf("could not reset to %s: %w", revision, err)
+	}
+	return nil
+}
+
+// Restore restores the paths in the working tree and/or the index from
+// opts.Source.
+func (r *Repository) Restore(paths []string, opts RestoreOptions) error {
+	if len(paths) == 0 {
+		return ErrNoPaths
+	}
+	if opts.Source != "" {
+		err := validateRefArguments(opts.Source)
+		if err != nil {
+			return err
+		}
+	}
+	worktree := opts.Worktree || !opts.Staged
+
+	supportsRestore, err := r.checkGitVersion(">= 2.23")
+	if err != nil {
+		return err
+	}
+
+	if supportsRestore {
+		// git restore [--source=<revision>] [--staged] [--worktree] -- <paths>
+		command := script.LocalCommandFrom("git restore")
+		if opts.Source != "" {
+			command.Add("--source=" + opts.Source)
+		}
+		if opts.Staged {
+			command.Add("--staged")
+		}
+		if worktree {
+			command.Add("--worktree")
+		}
+		command.Add("--")
+		command.AddAll(paths...)
+
+		_, err = r.run(command)
+		if err != nil {
+			return fmt.Errorf("could not restore %s: %w", strings.Join(paths, ", "), err)
+		}
+		return nil
+	}
+
+	// Fallback
+	if opts.Staged {
+		// git reset --quiet <revision> -- <paths>
+		source := opts.Source
+		if source == "" {
+			source = "HEAD"
+		}
+		command := script.LocalCommandFrom("git reset --quiet")
+		command.AddAll(source, "--")
+		command.AddAll(paths...)
+
+		_, err = r.run(command)
+		if err != nil {
+			return fmt.Errorf("could not restore %s: %w", strings.Join(paths, ", "), err)
+		}
+	}
+	if worktree {
+		// git checkout [<revision>] -- <paths>
+		// a source revision updates the index too, restoring both from the index
+		// means restoring from HEAD as it was reset above
+		command := script.LocalCommandFrom("git checkout")
+		if opts.Source != "" {
+			command.Add(opts.Source)
+		} else if opts.Staged {
+			command.Add("HEAD")
+		}
+		command.Add("--")
+		command.AddAll(paths...)
+
+		_, err = r.run(command)
+		if err != nil {
+			return fmt.Errorf("could not restore %s: %w", strings.Join(paths, ", "), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// FileState is the state of a file in the index or the working tree as shown
+// by git status.
+type FileState byte
+
+const (
+	Unmodified  FileState = '.'
+	Modified    FileState = 'M'
+	TypeChanged FileState = 'T'
+	Added       FileState = 'A'
+	Deleted     FileState = 'D'
+	Renamed     FileState = 'R'
+	Copied      FileState = 'C'
+	// Unmerged is set for conflicted files
+	Unmerged FileState = 'U'
+)
+
+func (s FileState) String() string {
+	return string(s)
+}
+
+// StatusEntryKind distinguishes the entry types of git status --porcelain=v2.
+type StatusEntryKind int
+
+const (
+	// ChangedEntry is a tracked file with changes
+	ChangedEntry StatusEntryKind = iota
+	// RenamedEntry is a renamed or copied file, OrigPath is set
+	RenamedEntry
+	// ConflictedEntry is a file with merge conflicts
+	ConflictedEntry
+	UntrackedEntry
+	IgnoredEntry
+)
+
+// SubmoduleState describes the changes of a submodule entry.
+type SubmoduleState struct {
+	IsSubmodule         bool
+	CommitChanged       bool
+	HasTrackedChanges   bool
+	HasUntrackedChanges bool
+}
+
+// StatusEntry is a file listed by git status.
+type StatusEntry struct {
+	Kind StatusEntryKind
+	// Staged is the state in the index compared to HEAD
+	Staged FileState
+	// Unstaged is the state in the working tree compared to the index
+	Unstaged FileState
+	Path     string
+	// OrigPath is the source of a rename or copy
+	OrigPath  string
+	Submodule SubmoduleState
+
+	// Score is the similarity of a rename or copy like "R100"
+	Score string
+	// ModeHead, ModeIndex and ModeWorktree are octal file modes
+	ModeHead     string
+	ModeIndex    string
+	ModeWorktree string
+	HashHead     string
+	HashIndex    string
+}
+
+// StatusBranch is the branch information of git status --branch.
+type StatusBranch struct {
+	// Commit is "" on an unborn branch
+	Commit string
+	// Head is the current branch, "" if detached
+	Head     string
+	Detached bool
+	// Upstream is like "origin/main", "" if none is set
+	Upstream string
+	// Ahead and Behind are only valid if HasAheadBehind is true, it is false if
+	// there is no upstream or it is gone
+	Ahead          int
+	Behind         int
+	HasAheadBehind bool
+}
+
+// Status is the state of the working tree and the index.
+type Status struct {
+	Branch  StatusBranch
+	Entries []*StatusEntry
+}
+
+// StatusOptions configures Repository.StatusWithOptions.
+type StatusOptions struct {
+	// Ignored lists ignored files too
+	Ignored bool
+	// Paths limits the status to the given pathspecs
+	Paths []string
+}
+
+// Status returns the status of the working tree including untracked files.
+func (r *Repository) Status() (*Status, error) {
+	return r.StatusWithOptions(StatusOptions{})
+}
+
+func (r *Repository) StatusWithOptions(opts StatusOptions) (*Status, error) {
+	// https://git-scm.com/docs/git-status#_porcelain_format_version_2
+	// git status --porcelain=v2 -z --branch --untracked-files=all [--ignored] -- [<paths>]
+	command := script.LocalCommandFrom("git status --porcelain=v2 -z --branch --untracked-files=all")
+	if opts.Ignored {
+		command.Add("--ignored")
+	}
+	command.Add("--")
+	command.AddAll(opts.Paths...)
+
+	pr, err := r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not get status: %w", err)
+	}
+	return parseStatus(pr.Output())
+}
+
+func parseStatus(output string) (*Status, error) {
+	status := Status{
+		Entries: []*StatusEntry{},
+	}
+
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
+			continue
+		}
+
+		var (
+			entry *StatusEntry
+			err   error
+		)
+		switch record[0] {
+		case '#':
+			err = status.Branch.parseHeader(record)
+		case '1':
+			entry, err = parseChangedStatusEntry(record)
+		case '2':
+			// the original path is the next record
+			if i+1 >= len(records) {
+				return nil, fmt.Errorf("missing original path in status entry: %s", record)
+			}
+			i++
+			entry, err = parseRenamedStatusEntry(record, records[i])
+		case 'u':
+			entry, err = parseConflictedStatusEntry(record)
+		case '?', '!':
+			// ? <path> or ! <path>
+			if len(record) < 3 || record[1] != ' ' {
+				return nil, fmt.Errorf("invalid status entry: %s", record)
+			}
+			kind := UntrackedEntry
+			if record[0] == '!' {
+				kind = IgnoredEntry
+			}
+			entry = &StatusEntry{Kind: kind, Staged: Unmodified, Unstaged: Unmodified, Path: record[2:]}
+		default:
+			err = fmt.Errorf("invalid status entry: %s", record)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			status.Entries = append(status.Entries, entry)
+		}
+	}
+	return &status, nil
+}
+
+func (b *StatusBranch) parseHeader(record string) error {
+	fields := strings.SplitN(record, " ", 3)
+	if len(fields) != 3 {
+		return fmt.Errorf("invalid status header: %s", record)
+	}
+	value := fields[2]
+	switch fields[1] {
+	case "branch.oid":
+		if value != "(initial)" {
+			b.Commit = value
+		}
+	case "branch.head":
+		if value == "(detached)" {
+			b.Detached = true
+		} else {
+			b.Head = value
+		}
+	case "branch.upstream":
+		b.Upstream = value
+	case "branch.ab":
+		// +<ahead> -<behind>
+		_, err := fmt.Sscanf(value, "+%d -%d", &b.Ahead, &b.Behind)
+		if err != nil {
+			return fmt.Errorf("invalid status header: %s", record)
+		}
+		b.HasAheadBehind = true
+	}
+	// unknown headers are skipped for forward compatibility
+	return nil
+}
+
+// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+func parseChangedStatusEntry(record string) (*StatusEntry, error) {
+	fields := strings.SplitN(record, " ", 9)
+	if len(fields) != 9 {
+		return nil, fmt.Errorf("invalid status entry: %s", record)
+	}
+	entry := StatusEntry{
+		Kind:         ChangedEntry,
+		ModeHead:     fields[3],
+		ModeIndex:    fields[4],
+		ModeWorktree: fields[5],
+		HashHead:     fields[6],
+		HashIndex:    fields[7],
+		Path:         fields[8],
+	}
+	return &entry, entry.parseStates(fields[1], fields[2])
+}
+
+// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>, followed by <origPath>
+func parseRenamedStatusEntry(record, origPath string) (*StatusEntry, error) {
+	fields := strings.SplitN(record, " ", 10)
+	if len(fields) != 10 {
+		return nil, fmt.Errorf("invalid status entry: %s", record)
+	}
+	entry := StatusEntry{
+		Kind:         RenamedEntry,
+		ModeHead:     fields[3],
+		ModeIndex:    fields[4],
+		ModeWorktree: fields[5],
+		HashHead:     fields[6],
+		HashIndex:    fields[7],
+		Score:        fields[8],
+		Path:         fields[9],
+		OrigPath:     origPath,
+	}
+	return &entry, entry.parseStates(fields[1], fields[2])
+}
+
+// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+func parseConflictedStatusEntry(record string) (*StatusEntry, error) {
+	fields := strings.SplitN(record, " ", 11)
+	if len(fields) != 11 {
+		return nil, fmt.Errorf("invalid status entry: %s", record)
+	}
+	entry := StatusEntry{
+		Kind:         ConflictedEntry,
+		ModeWorktree: fields[6],
+		Path:         fields[10],
+	}
+	return &entry, entry.parseStates(fields[1], fields[2])
+}
+
+// parseStates parses <XY> and <sub> like "N..." or "SCMU".
+func (e *StatusEntry) parseStates(xy, sub string) error {
+	if len(xy) != 2 || len(sub) != 4 {
+		return fmt.Errorf("invalid status entry states: %s %s", xy, sub)
+	}
+	e.Staged = FileState(xy[0])
+	e.Unstaged = FileState(xy[1])
+	if sub[0] == 'S' {
+		e.Submodule = SubmoduleState{
+			IsSubmodule:         true,
+			CommitChanged:       sub[1] == 'C',
+			HasTrackedChanges:   sub[2] == 'M',
+			HasUntrackedChanges: sub[3] == 'U',
+		}
+	}
+	return nil
+}
+
+// IsStaged returns true iff the entry has changes in the index.
+func (e *StatusEntry) IsStaged() bool {
+	return (e.Kind == ChangedEntry || e.Kind == RenamedEntry) && e.Staged != Unmodified
+}
+
+// IsUnstaged returns true iff the entry has changes in the working tree not
+// added to the index.
+func (e *StatusEntry) IsUnstaged() bool {
+	return (e.Kind == ChangedEntry || e.Kind == RenamedEntry) && e.Unstaged != Unmodified
+}
+
+func (e *StatusEntry) IsUntracked() bool {
+	return e.Kind == UntrackedEntry
+}
+
+func (e *StatusEntry) IsIgnored() bool {
+	return e.Kind == IgnoredEntry
+}
+
+func (e *StatusEntry) IsConflicted() bool {
+	return e.Kind == ConflictedEntry
+}
+
+func (e *StatusEntry) String() string {
+	switch e.Kind {
+	case UntrackedEntry:
+		return "?? " + e.Path
+	case IgnoredEntry:
+		return "!! " + e.Path
+	case RenamedEntry:
+		return fmt.Sprintf("%s%s %s -> %s", e.Staged, e.Unstaged, e.OrigPath, e.Path)
+	}
+	return fmt.Sprintf("%s%s %s", e.Staged, e.Unstaged, e.Path)
+}
+
+// IsClean returns true iff there are neither changes nor untracked files,
+// ignored files are not considered.
+func (s *Status) IsClean() bool {
+	for _, entry := range s.Entries {
+		if !entry.IsIgnored() {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Status) HasUntracked() bool {
+	for _, entry := range s.Entries {
+		if entry.IsUntracked() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Status) HasConflicts() bool {
+	for _, entry := range s.Entries {
+		if entry.IsConflicted() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStaged returns true iff there are changes in the index to be committed.
+func (s *Status) HasStaged() bool {
+	for _, entry := range s.Entries {
+		if entry.IsStaged() {
+			return true
+		}
+	}
+	return false
+}
+
+func (b StatusBranch) String() string {
+	head := b.Head
+	if b.Detached {
+		head = "HEAD (detached at " + b.Commit + ")"
+	}
+	if b.Upstream == "" {
+		return head
+	}
+	if !b.HasAheadBehind {
+		return head + "..." + b.Upstream + " [gone]"
+	}
+	return head + "..." + b.Upstream + " [ahead " + strconv.Itoa(b.Ahead) + ", behind " + strconv.Itoa(b.Behind) + "]"
+}
@@ -0,0 +1,153 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	output := "# branch.oid 1234567890abcdef1234567890abcdef12345678\x00" +
+		"# branch.head main\x00" +
+		"# branch.upstream origin/main\x00" +
+		"# branch.ab +2 -1\x00" +
+		"# stash 3\x00" +
+		"1 M. N... 100644 100644 100644 aaaa bbbb staged file.txt\x00" +
+		"1 .M N... 100644 100644 100755 aaaa aaaa with space.sh\x00" +
+		"2 R. N... 100644 100644 100644 cccc cccc R100 new.txt\x00old.txt\x00" +
+		"u UU N... 100644 100644 100644 100644 dddd eeee ffff conflict.txt\x00" +
+		"1 .M SCMU 160000 160000 160000 gggg gggg module\x00" +
+		"? untracked.txt\x00" +
+		"! ignored.log\x00"
+
+	status, err := parseStatus(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedBranch := StatusBranch{
+		Commit:         "1234567890abcdef1234567890abcdef12345678",
+		Head:           "main",
+		Upstream:       "origin/main",
+		Ahead:          2,
+		Behind:         1,
+		HasAheadBehind: true,
+	}
+	if status.Branch != expectedBranch {
+		t.Errorf("expected branch %+v, got %+v", expectedBranch, status.Branch)
+	}
+	if actual := status.Branch.String(); actual != "main...origin/main [ahead 2, behind 1]" {
+		t.Errorf("unexpected branch string %q", actual)
+	}
+
+	expectedEntries := []*StatusEntry{
+		{Kind: ChangedEntry, Staged: Modified, Unstaged: Unmodified, Path: "staged file.txt",
+			ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100644", HashHead: "aaaa", HashIndex: "bbbb"},
+		{Kind: ChangedEntry, Staged: Unmodified, Unstaged: Modified, Path: "with space.sh",
+			ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100755", HashHead: "aaaa", HashIndex: "aaaa"},
+		{Kind: RenamedEntry, Staged: Renamed, Unstaged: Unmodified, Path: "new.txt", OrigPath: "old.txt", Score: "R100",
+			ModeHead: "100644", ModeIndex: "100644", ModeWorktree: "100644", HashHead: "cccc", HashIndex: "cccc"},
+		{Kind: ConflictedEntry, Staged: Unmerged, Unstaged: Unmerged, Path: "conflict.txt", ModeWorktree: "100644"},
+		{Kind: ChangedEntry, Staged: Unmodified, Unstaged: Modified, Path: "module",
+			Submodule: SubmoduleState{IsSubmodule: true, CommitChanged: true, HasTrackedChanges: true, HasUntrackedChanges: true},
+			ModeHead:  "160000", ModeIndex: "160000", ModeWorktree: "160000", HashHead: "gggg", HashIndex: "gggg"},
+		{Kind: UntrackedEntry, Staged: Unmodified, Unstaged: Unmodified, Path: "untracked.txt"},
+		{Kind: IgnoredEntry, Staged: Unmodified, Unstaged: Unmodified, Path: "ignored.log"},
+	}
+	if len(status.Entries) != len(expectedEntries) {
+		t.Fatalf("expected %d entries, got %d", len(expectedEntries), len(status.Entries))
+	}
+	for i, expected := range expectedEntries {
+		if !reflect.DeepEqual(status.Entries[i], expected) {
+			t.Errorf("entry %d: expected %+v, got %+v", i, expected, status.Entries[i])
+		}
+	}
+
+	predicates := []struct {
+		staged, unstaged, untracked, ignored, conflicted bool
+		str                                              string
+	}{
+		{true, false, false, false, false, "M. staged file.txt"},
+		{false, true, false, false, false, ".M with space.sh"},
+		{true, false, false, false, false, "R. old.txt -> new.txt"},
+		{false, false, false, false, true, "UU conflict.txt"},
+		{false, true, false, false, false, ".M module"},
+		{false, false, true, false, false, "?? untracked.txt"},
+		{false, false, false, true, false, "!! ignored.log"},
+	}
+	for i, p := range predicates {
+		entry := status.Entries[i]
+		actual := []bool{entry.IsStaged(), entry.IsUnstaged(), entry.IsUntracked(), entry.IsIgnored(), entry.IsConflicted()}
+		expected := []bool{p.staged, p.unstaged, p.untracked, p.ignored, p.conflicted}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("entry %d: expected predicates %v, got %v", i, expected, actual)
+		}
+		if entry.String() != p.str {
+			t.Errorf("entry %d: expected %q, got %q", i, p.str, entry.String())
+		}
+	}
+
+	if status.IsClean() || !status.HasUntracked() || !status.HasConflicts() || !status.HasStaged() {
+		t.Errorf("unexpected status predicates for %v", status.Entries)
+	}
+}
+
+func TestParseStatusBranch(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected StatusBranch
+		str      string
+	}{
+		{
+			output:   "# branch.oid (initial)\x00# branch.head main\x00",
+			expected: StatusBranch{Head: "main"},
+			str:      "main",
+		},
+		{
+			output:   "# branch.oid abc\x00# branch.head (detached)\x00",
+			expected: StatusBranch{Commit: "abc", Detached: true},
+			str:      "HEAD (detached at abc)",
+		},
+		{
+			// the upstream branch is gone
+			output:   "# branch.oid abc\x00# branch.head feature\x00# branch.upstream origin/feature\x00",
+			expected: StatusBranch{Commit: "abc", Head: "feature", Upstream: "origin/feature"},
+			str:      "feature...origin/feature [gone]",
+		},
+	}
+	for _, test := range tests {
+		status, err := parseStatus(test.output)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.output, err)
+			continue
+		}
+		if status.Branch != test.expected {
+			t.Errorf("%q: expected %+v, got %+v", test.output, test.expected, status.Branch)
+		}
+		if status.Branch.String() != test.str {
+			t.Errorf("%q: expected %q, got %q", test.output, test.str, status.Branch.String())
+		}
+		if !status.IsClean() {
+			t.Errorf("%q: expected clean status", test.output)
+		}
+	}
+}
+
+func TestParseStatusInvalid(t *testing.T) {
+	for _, output := range []string{
+		"# branch.ab x\x00",
+		"# branch.head\x00",
+		"1 M. N... 100644\x00",
+		"2 R. N... 100644 100644 100644 cccc cccc R100 new.txt",
+		"1 M N... 100644 100644 100644 aaaa bbbb file.txt\x00",
+		"x something\x00",
+		"?\x00",
+		"!\x00",
+		"? \x00",
+		"?x\x00",
+	} {
+		_, err := parseStatus(output)
+		if err == nil {
+			t.Errorf("%q: expected error", output)
+		}
+	}
+}
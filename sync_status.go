@@ -0,0 +1,152 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyncStatus describes how a local branch diverged from its upstream.
+type SyncStatus struct {
+	Branch *LocalBranch
+	// Upstream is the full ref name of the configured upstream, "" if none
+	Upstream     string
+	UpstreamGone bool
+	Ahead        int
+	Behind       int
+
+	// AheadCommits and BehindCommits are only filled by LocalBranch.GetSyncStatus
+	AheadCommits  []*Commit
+	BehindCommits []*Commit
+}
+
+// HasUpstream returns true iff an upstream is configured for the branch.
+func (s *SyncStatus) HasUpstream() bool {
+	return s.Upstream != ""
+}
+
+// IsInSync returns true iff the branch has an existing upstream pointing to the
+// same commit.
+func (s *SyncStatus) IsInSync() bool {
+	return s.HasUpstream() && !s.UpstreamGone && s.Ahead == 0 && s.Behind == 0
+}
+
+func (s *SyncStatus) String() string {
+	switch {
+	case !s.HasUpstream():
+		return fmt.Sprintf("%s: no upstream", s.Branch)
+	case s.UpstreamGone:
+		return fmt.Sprintf("%s: upstream %s gone", s.Branch, s.Upstream)
+	}
+	return fmt.Sprintf("%s: %d ahead, %d behind %s", s.Branch, s.Ahead, s.Behind, s.Upstream)
+}
+
+// GetSyncStatus compares the branch with its upstream, including the commits
+// only present on either side.
+func (b *LocalBranch) GetSyncStatus() (*SyncStatus, error) {
+	// always up to date, not using the cached info
+	info, err := b.repository.loadBranchInfo("refs/heads/", b.GetName())
+	if err != nil {
+		return nil, err
+	}
+	status, err := newSyncStatus(b, info)
+	if err != nil {
+		return nil, err
+	}
+	if !status.HasUpstream() || status.UpstreamGone {
+		return status, nil
+	}
+
+	branchRef := "refs/heads/" + b.GetName()
+	status.AheadCommits, err = b.repository.listCommits(status.Upstream + ".." + branchRef)
+	if err != nil {
+		return nil, err
+	}
+	status.BehindCommits, err = b.repository.listCommits(branchRef + ".." + status.Upstream)
+	if err != nil {
+		return nil, err
+	}
+	// consistent with the listed commits even if refs changed in between
+	status.Ahead = len(status.AheadCommits)
+	status.Behind = len(status.BehindCommits)
+	return status, nil
+}
+
+// GetSyncStatuses compares all local branches with their upstreams using a
+// single git command. The commits on either side are not listed.
+func (r *Repository) GetSyncStatuses() ([]*SyncStatus, error) {
+	branches, err := r.ListBranches(BranchListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*SyncStatus, 0, len(branches))
+	for _, branch := range branches {
+		status, err := newSyncStatus(branch, branch.info)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func newSyncStatus(branch *LocalBranch, info *BranchInfo) (*SyncStatus, error) {
+	ahead, behind, gone, err := parseUpstreamTrack(info.upstreamTrack)
+	if err != nil {
+		return nil, err
+	}
+	status := SyncStatus{
+		Branch:        branch,
+		Upstream:      info.Upstream,
+		UpstreamGone:  gone,
+		Ahead:         ahead,
+		Behind:        behind,
+		AheadCommits:  []*Commit{},
+		BehindCommits: []*Commit{},
+	}
+	return &status, nil
+}
+
+// parseUpstreamTrack parses %(upstream:track) of git for-each-ref like
+// "[ahead 1, behind 2]" or "[gone]". It is empty if in sync or no upstream is set.
+func parseUpstreamTrack(track string) (ahead, behind int, gone bool, err error) {
+	track = strings.TrimSuffix(strings.TrimPrefix(track, "["), "]")
+	if track == "" {
+		return 0, 0, false, nil
+	}
+	if track == "gone" {
+		return 0, 0, true, nil
+	}
+
+	for _, part := range strings.Split(track, ", ") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return 0, 0, false, fmt.Errorf("invalid upstream track format: %s", track)
+		}
+		count, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0, 0, false, fmt.Errorf("invalid upstream track format: %s", track)
+		}
+		switch fields[0] {
+		case "ahead":
+			ahead = count
+		case "behind":
+			behind = count
+		default:
+			return 0, 0, false, fmt.Errorf("invalid upstream track format: %s", track)
+		}
+	}
+	return ahead, behind, false, nil
+}
+
+// listCommits returns all commits of a revision range.
+func (r *Repository) listCommits(revisionRange string) ([]*Commit, error) {
+	it, err := r.Log(LogOptions{
+		Revisions: []string{revisionRange},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return it.All()
+}
@@ -0,0 +1,39 @@
+package git
+
+import "testing"
+
+func TestParseUpstreamTrack(t *testing.T) {
+	tests := []struct {
+		track  string
+		ahead  int
+		behind int
+		gone   bool
+		err    bool
+	}{
+		{"", 0, 0, false, false},
+		{"[ahead 1]", 1, 0, false, false},
+		{"[behind 12]", 0, 12, false, false},
+		{"[ahead 3, behind 4]", 3, 4, false, false},
+		{"[gone]", 0, 0, true, false},
+		{"[ahead x]", 0, 0, false, true},
+		{"[sideways 1]", 0, 0, false, true},
+		{"[ahead]", 0, 0, false, true},
+	}
+	for _, test := range tests {
+		ahead, behind, gone, err := parseUpstreamTrack(test.track)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", test.track)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.track, err)
+			continue
+		}
+		if ahead != test.ahead || behind != test.behind || gone != test.gone {
+			t.Errorf("%q: expected ahead %d, behind %d, gone %v, got %d, %d, %v",
+				test.track, test.ahead, test.behind, test.gone, ahead, behind, gone)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jojomi/go-script/v2"
+)
+
+// Tag is a local tag, either lightweight or annotated. Its metadata is loaded
+// when retrieving it.
+type Tag struct {
+	name string
+	// hash of the tag object for annotated tags, of the tagged object otherwise
+	hash      string
+	annotated bool
+
+	// only set for annotated tags
+	message     string
+	taggerName  string
+	taggerEmail string
+	taggerDate  time.Time
+
+	repository *Repository
+}
+
+// CreateTagOptions configures Repository.CreateTag.
+type CreateTagOptions struct {
+	// Annotated creates a tag object, it is implied by a non-empty Message
+	Annotated bool
+	Message   string
+	// TaggerName, TaggerEmail and TaggerDate override the configured identity
+	// and the current time for annotated tags
+	TaggerName  string
+	TaggerEmail string
+	TaggerDate  time.Time
+	// Force replaces an existing tag
+	Force bool
+}
+
+// https://git-scm.com/docs/git-for-each-ref#_field_names
+var tagPlaceholders = []string{
+	"%(refname)", "%(objecttype)", "%(objectname)",
+	"%(taggername)", "%(taggeremail)", "%(taggerdate:unix)", "%(contents)",
+	"%(contents:signature)",
+}
+
+func (r *Repository) GetTags() ([]*Tag, error) {
+	return r.listTags("refs/tags/")
+}
+
+func (r *Repository) GetTag(name string) (*Tag, error) {
+	tags, err := r.listTags("refs/tags/" + name)
+	if err != nil {
+		return nil, err
+	}
+	// the pattern also matches tags in a directory of that name
+	for _, tag := range tags {
+		if tag.GetName() == name {
+			return tag, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrTagNotFound, name)
+}
+
+func (r *Repository) HasTag(name string) (bool, error) {
+	// git show-ref --verify --quiet refs/tags/<tag-name>
+	command := script.LocalCommandFrom("git show-ref --verify --quiet")
+	command.Add("refs/tags/" + name)
+
+	return r.refExists(command)
+}
+
+// CreateTag creates a tag pointing to revision (HEAD if empty).
+func (r *Repository) CreateTag(name, revision string, opts CreateTagOptions) (*Tag, error) {
+	err := validateRefArguments(name, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// git tag [--annotate --message=<message>] [--force] -- <name> [<revision>]
+	command := script.LocalCommandFrom("git tag")
+	var env []string
+	if opts.Annotated || opts.Message != "" {
+		command.AddAll("--annotate", "--message="+opts.Message)
+		env = identityEnv("COMMITTER", opts.TaggerName, opts.TaggerEmail, opts.TaggerDate)
+	}
+	if opts.Force {
+		command.Add("--force")
+	}
+	command.AddAll("--", name)
+	if revision != "" {
+		command.Add(revision)
+	}
+
+	_, err = r.runInvocation(&Invocation{
+		Command: command,
+		Env:     env,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create tag %s: %w", name, err)
+	}
+	return r.GetTag(name)
+}
+
+// listTags lists the tags matching the for-each-ref pattern.
+func (r *Repository) listTags(pattern string) ([]*Tag, error) {
+	// https://git-scm.com/docs/git-for-each-ref
+	// git for-each-ref --format=<format> <pattern>
+	// every field including the last one is terminated by NUL, as messages may
+	// contain newlines
+	command := script.LocalCommandFrom("git for-each-ref")
+	command.AddAll("--format="+strings.Join(tagPlaceholders, "%00")+"%00", pattern)
+	pr, err := r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags: %w", err)
+	}
+
+	tags := make([]*Tag, 0)
+	fields := strings.Split(pr.Output(), "\x00")
+	// the last element is the final newline
+	for i := 0; i+len(tagPlaceholders) <= len(fields); i += len(tagPlaceholders) {
+		tag, err := r.parseTag(fields[i : i+len(tagPlaceholders)])
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (r *Repository) parseTag(fields []string) (*Tag, error) {
+	// records are separated by newlines
+	refName := strings.TrimPrefix(fields[0], "\n")
+
+	tag := Tag{
+		name:       strings.TrimPrefix(refName, "refs/tags/"),
+		hash:       fields[2],
+		annotated:  fields[1] == "tag",
+		repository: r,
+	}
+	if !tag.annotated {
+		return &tag, nil
+	}
+
+	tag.taggerName = fields[3]
+	tag.taggerEmail = strings.TrimSuffix(strings.TrimPrefix(fields[4], "<"), ">")
+	if fields[5] != "" {
+		timestamp, err := strconv.ParseInt(fields[5], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		tag.taggerDate = time.Unix(timestamp, 0)
+	}
+	// the contents include the signature of signed tags
+	tag.message = strings.TrimSpace(strings.TrimSuffix(fields[6], fields[7]))
+	return &tag, nil
+}
+
+func (t *Tag) GetName() string {
+	return t.name
+}
+
+func (t *Tag) GetFullName() string {
+	return "refs/tags/" + t.name
+}
+
+// GetHash returns the hash of the tag object for annotated tags, of the tagged
+// object otherwise.
+func (t *Tag) GetHash() string {
+	return t.hash
+}
+
+func (t *Tag) IsAnnotated() bool {
+	return t.annotated
+}
+
+// GetMessage returns the message of an annotated tag, "" for lightweight tags.
+func (t *Tag) GetMessage() string {
+	return t.message
+}
+
+// GetTaggerName returns the tagger of an annotated tag, "" for lightweight tags.
+func (t *Tag) GetTaggerName() string {
+	return t.taggerName
+}
+
+// GetTaggerEmail returns the tagger of an annotated tag, "" for lightweight tags.
+func (t *Tag) GetTaggerEmail() string {
+	return t.taggerEmail
+}
+
+// GetTaggerDate returns the creation date of an annotated tag, the zero time for
+// lightweight tags.
+func (t *Tag) GetTaggerDate() time.Time {
+	return t.taggerDate
+}
+
+// GetCommit returns the tagged commit, peeling nested tags.
+func (t *Tag) GetCommit() (*Commit, error) {
+	// git rev-parse --verify <tag>^{commit}
+	command := script.LocalCommandFrom("git rev-parse --verify")
+	command.Add(t.GetFullName() + "^{commit}")
+
+	pr, err := t.repository.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not get commit of tag %s: %w", t.GetName(), err)
+	}
+	return newCommit(t.repository, pr.TrimmedOutput())
+}
+
+// Delete deletes the local tag.
+func (t *Tag) Delete() error {
+	// git tag --delete -- <name>
+	command := script.LocalCommandFrom("git tag --delete --")
+	command.Add(t.GetName())
+
+	_, err := t.repository.run(command)
+	if err != nil {
+		return fmt.Errorf("could not delete tag %s: %w", t.GetName(), err)
+	}
+	return nil
+}
+
+// Push pushes the tag to remote.
+func (t *Tag) Push(remote *Remote) error {
+	// git push <remote> refs/tags/<name>
+	command := script.LocalCommandFrom("git push")
+	command.AddAll(remote.GetName(), t.GetFullName())
+
+	_, err := t.repository.run(command)
+	if err != nil {
+		return fmt.Errorf("could not push tag %s to %s: %w", t.GetName(), remote.GetName(), err)
+	}
+	return nil
+}
+
+func (t *Tag) Equals(otherTag *Tag) bool {
+	return t.GetName() == otherTag.GetName()
+}
+
+func (t *Tag) String() string {
+	return "Tag " + t.GetName()
+}
+
+// RemoteTag is a tag on a remote as listed by git ls-remote.
+type RemoteTag struct {
+	name string
+	// hash of the tag object for annotated tags, of the tagged object otherwise
+	hash       string
+	commitHash string
+
+	repository *Repository
+	remote     *Remote
+}
+
+// GetTags lists the tags on the remote, the remote is contacted for this.
+func (r *Remote) GetTags() ([]*RemoteTag, error) {
+	// git ls-remote --tags <remote>
+	command := script.LocalCommandFrom("git ls-remote --tags")
+	command.Add(r.GetName())
+
+	pr, err := r.repository.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags on %s: %w", r.GetName(), err)
+	}
+
+	tags := make([]*RemoteTag, 0)
+	byName := make(map[string]*RemoteTag)
+	for _, line := range strings.Split(pr.TrimmedOutput(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		name := strings.TrimPrefix(fields[1], "refs/tags/")
+
+		// peeled annotated tag, listed after the tag itself
+		if strings.HasSuffix(name, "^{}") {
+			if tag, found := byName[strings.TrimSuffix(name, "^{}")]; found {
+				tag.commitHash = fields[0]
+			}
+			continue
+		}
+
+		tag := RemoteTag{
+			name:       name,
+			hash:       fields[0],
+			commitHash: fields[0],
+			repository: r.repository,
+			remote:     r,
+		}
+		byName[name] = &tag
+		tags = append(tags, &tag)
+	}
+	return tags, nil
+}
+
+// DeleteTag deletes the tag name on the remote.
+func (r *Remote) DeleteTag(name string) error {
+	// git push <remote> --delete refs/tags/<name>
+	command := script.LocalCommandFrom("git push")
+	command.AddAll(r.GetName(), "--delete", "refs/tags/"+name)
+
+	_, err := r.repository.run(command)
+	if err != nil {
+		return fmt.Errorf("could not delete tag %s on %s: %w", name, r.GetName(), err)
+	}
+	return nil
+}
+
+func (t *RemoteTag) GetName() string {
+	return t.name
+}
+
+func (t *RemoteTag) GetRemote() *Remote {
+	return t.remote
+}
+
+// GetHash returns the hash of the tag object for annotated tags, of the tagged
+// object otherwise.
+func (t *RemoteTag) GetHash() string {
+	return t.hash
+}
+
+// GetCommitHash returns the hash of the tagged object, which might not be
+// available locally.
+func (t *RemoteTag) GetCommitHash() string {
+	return t.commitHash
+}
+
+func (t *RemoteTag) Delete() error {
+	return t.remote.DeleteTag(t.GetName())
+}
+
+func (t *RemoteTag) String() string {
+	return fmt.Sprintf("Remote tag %s @ %s", t.GetName(), t.remote.GetName())
+}
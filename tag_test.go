@@ -0,0 +1,88 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTags(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	git("commit", "--quiet", "--allow-empty", "-m", "first")
+	head := git("rev-parse", "HEAD")
+
+	_, err := r.CreateTag("lightweight", "", CreateTagOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	annotated, err := r.CreateTag("annotated", "HEAD", CreateTagOptions{
+		Message:     "release\n\nnotes",
+		TaggerName:  "Tagger",
+		TaggerEmail: "tagger@example.com",
+		TaggerDate:  date,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !annotated.IsAnnotated() || annotated.GetMessage() != "release\n\nnotes" ||
+		annotated.GetTaggerName() != "Tagger" || annotated.GetTaggerEmail() != "tagger@example.com" ||
+		!annotated.GetTaggerDate().Equal(date) {
+		t.Errorf("unexpected annotated tag %+v", annotated)
+	}
+	commit, err := annotated.GetCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.GetHash() != head {
+		t.Errorf("expected commit %s, got %s", head, commit.GetHash())
+	}
+
+	// a signed tag, the signature is not verified when listing
+	cmd := exec.Command("git", "mktag")
+	cmd.Dir = r.GetPath()
+	cmd.Env = os.Environ()
+	cmd.Stdin = strings.NewReader("object " + head + "\ntype commit\ntag signed\ntagger Tagger <tagger@example.com> 1600000000 +0000\n\n" +
+		"signed release\n-----BEGIN PGP SIGNATURE-----\n\nc2lnbmF0dXJl\n-----END PGP SIGNATURE-----\n")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	git("update-ref", "refs/tags/signed", strings.TrimSpace(string(output)))
+
+	tags, err := r.GetTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(map[string]string)
+	for _, tag := range tags {
+		messages[tag.GetName()] = tag.GetMessage()
+	}
+	expected := map[string]string{
+		"annotated":   "release\n\nnotes",
+		"lightweight": "",
+		"signed":      "signed release",
+	}
+	for name, message := range expected {
+		actual, found := messages[name]
+		if !found || actual != message {
+			t.Errorf("tag %s: expected message %q, got %q (found: %v)", name, message, actual, found)
+		}
+	}
+
+	err = annotated.Delete()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exists, err := r.HasTag("annotated")
+	if err != nil || exists {
+		t.Errorf("expected deleted tag, got exists=%v, err=%v", exists, err)
+	}
+	_, err = r.GetTag("annotated")
+	if !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("expected ErrTagNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepository initializes a repository with branch main in a temporary
+// directory, isolated from the user's git configuration. The returned function
+// runs git in it and fails the test on errors.
+func newTestRepository(t *testing.T, bare bool) (*Repository, func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "author@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Committer")
+	t.Setenv("GIT_COMMITTER_EMAIL", "committer@example.com")
+
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "LC_ALL=C")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	args := []string{"init", "--quiet"}
+	if bare {
+		args = append(args, "--bare")
+	}
+	git(args...)
+	git("symbolic-ref", "HEAD", "refs/heads/main")
+
+	r, err := OpenRepository(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, git
+}
+
+// writeFile writes a file relative to the work tree of r.
+func writeFile(t *testing.T, r *Repository, name, content string) {
+	t.Helper()
+	err := os.WriteFile(filepath.Join(r.GetPath(), name), []byte(content), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,246 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/jojomi/go-script/v2"
+)
+
+// VersionTag is a tag whose name is a semantic version.
+type VersionTag struct {
+	Tag     *Tag
+	Version *semver.Version
+}
+
+// VersionOptions configures selecting version tags.
+type VersionOptions struct {
+	// Prefix of the tag names in front of the version, e.g. "v"
+	Prefix string
+	// IgnorePrereleases skips versions like 1.2.0-rc.1
+	IgnorePrereleases bool
+	// ReachableFrom restricts the tags to those reachable from a revision like a
+	// branch name, all tags are considered if empty
+	ReachableFrom string
+}
+
+// VersionBump is the kind of change from one version to the next.
+type VersionBump int
+
+const (
+	BumpPatch VersionBump = iota
+	BumpMinor
+	BumpMajor
+	// BumpPrerelease creates or increments a prerelease of the next patch version
+	BumpPrerelease
+)
+
+func (b VersionBump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	case BumpPrerelease:
+		return "prerelease"
+	}
+	return "unknown"
+}
+
+// DefaultPrereleaseID is used by NextVersion if no prerelease identifier is given.
+const DefaultPrereleaseID = "rc"
+
+// major.minor.patch are required, semver.NewVersion would accept "1.2" too
+var regexpVersionTag = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+([-+].*)?$`)
+
+// GetVersionTags returns the tags consisting of prefix and a semantic version
+// like v1.2.3 for prefix "v", ordered by ascending version. Other tags are
+// skipped.
+func (r *Repository) GetVersionTags(prefix string) ([]*VersionTag, error) {
+	return r.getVersionTags(VersionOptions{
+		Prefix: prefix,
+	})
+}
+
+// GetLatestVersion returns the version tag with the highest version matching
+// opts. ErrNoVersionTag is returned if there is none.
+func (r *Repository) GetLatestVersion(opts VersionOptions) (*VersionTag, error) {
+	versionTags, err := r.getVersionTags(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(versionTags) == 0 {
+		return nil, fmt.Errorf("%w: prefix %q", ErrNoVersionTag, opts.Prefix)
+	}
+	return versionTags[len(versionTags)-1], nil
+}
+
+// GetNextVersion proposes the version following the latest version tag matching
+// opts, see NextVersion. If there is no version tag yet, it starts from 0.0.0.
+func (r *Repository) GetNextVersion(bump VersionBump, prereleaseID string, opts VersionOptions) (*semver.Version, error) {
+	current := semver.MustParse("0.0.0")
+	latest, err := r.GetLatestVersion(opts)
+	switch {
+	case err == nil:
+		current = latest.Version
+	case !errors.Is(err, ErrNoVersionTag):
+		return nil, err
+	}
+	return NextVersion(current, bump, prereleaseID)
+}
+
+// NextVersion returns the version following current. A prerelease is released
+// by the bump it belongs to, e.g. 1.3.0-rc.1 is followed by 1.3.0 for
+// BumpMinor. BumpPrerelease increments the last numeric identifier of an
+// existing prerelease with the same prereleaseID (1.3.0-rc.1 -> 1.3.0-rc.2) or
+// starts a prerelease of the next patch version (1.2.3 -> 1.2.4-rc.1). The
+// next patch version is also used if a prerelease with a different
+// prereleaseID would sort lower (1.3.0-rc.1 -> 1.3.1-beta.1). DefaultPrereleaseID
+// is used if prereleaseID is empty.
+func NextVersion(current *semver.Version, bump VersionBump, prereleaseID string) (*semver.Version, error) {
+	major, minor, patch := current.Major(), current.Minor(), current.Patch()
+	pre := current.Prerelease()
+
+	switch bump {
+	case BumpMajor:
+		if pre == "" || minor != 0 || patch != 0 {
+			major++
+		}
+		minor, patch, pre = 0, 0, ""
+	case BumpMinor:
+		if pre == "" || patch != 0 {
+			minor++
+		}
+		patch, pre = 0, ""
+	case BumpPatch:
+		if pre == "" {
+			patch++
+		}
+		pre = ""
+	case BumpPrerelease:
+		if prereleaseID == "" {
+			prereleaseID = DefaultPrereleaseID
+		}
+		if pre == "" {
+			patch++
+		}
+		pre = nextPrerelease(pre, prereleaseID)
+	default:
+		return nil, fmt.Errorf("invalid version bump %d", bump)
+	}
+
+	next, err := semver.NewVersion(formatVersion(major, minor, patch, pre))
+	if err != nil {
+		return nil, err
+	}
+	// another prerelease identifier may sort lower, e.g. 1.3.0-rc.1 -> 1.3.0-beta.1
+	if bump == BumpPrerelease && !next.GreaterThan(current) {
+		return semver.NewVersion(formatVersion(major, minor, patch+1, prereleaseID+".1"))
+	}
+	return next, nil
+}
+
+func formatVersion(major, minor, patch int64, pre string) string {
+	version := fmt.Sprintf("%d.%d.%d", major, minor, patch)
+	if pre != "" {
+		version += "-" + pre
+	}
+	return version
+}
+
+// nextPrerelease increments pre if it belongs to id, e.g. rc.1 -> rc.2, and
+// starts with id.1 otherwise.
+func nextPrerelease(pre, id string) string {
+	if pre != id && !strings.HasPrefix(pre, id+".") {
+		return id + ".1"
+	}
+	parts := strings.Split(pre, ".")
+	last, err := strconv.Atoi(parts[len(parts)-1])
+	if len(parts) == 1 || err != nil {
+		return pre + ".1"
+	}
+	parts[len(parts)-1] = strconv.Itoa(last + 1)
+	return strings.Join(parts, ".")
+}
+
+func (r *Repository) getVersionTags(opts VersionOptions) ([]*VersionTag, error) {
+	tags, err := r.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var reachable map[string]bool
+	if opts.ReachableFrom != "" {
+		reachable, err = r.getTagsReachableFrom(opts.ReachableFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	versionTags := make([]*VersionTag, 0)
+	for _, tag := range tags {
+		if reachable != nil && !reachable[tag.GetName()] {
+			continue
+		}
+		if !strings.HasPrefix(tag.GetName(), opts.Prefix) {
+			continue
+		}
+		v := strings.TrimPrefix(tag.GetName(), opts.Prefix)
+		if !regexpVersionTag.MatchString(v) {
+			continue
+		}
+		version, err := semver.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		if opts.IgnorePrereleases && version.Prerelease() != "" {
+			continue
+		}
+		versionTags = append(versionTags, &VersionTag{
+			Tag:     tag,
+			Version: version,
+		})
+	}
+
+	// stable for versions differing in metadata only
+	sort.SliceStable(versionTags, func(i, j int) bool {
+		return versionTags[i].Version.LessThan(versionTags[j].Version)
+	})
+	return versionTags, nil
+}
+
+// getTagsReachableFrom returns the names of the tags pointing to ancestors of revision.
+func (r *Repository) getTagsReachableFrom(revision string) (map[string]bool, error) {
+	err := validateRefArguments(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// git for-each-ref --merged=<revision> --format=%(refname) refs/tags/
+	command := script.LocalCommandFrom("git for-each-ref")
+	command.AddAll("--merged="+revision, "--format=%(refname)", "refs/tags/")
+	pr, err := r.run(command)
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags reachable from %s: %w", revision, err)
+	}
+
+	names := make(map[string]bool)
+	for _, line := range strings.Split(pr.TrimmedOutput(), "\n") {
+		if line == "" {
+			continue
+		}
+		names[strings.TrimPrefix(line, "refs/tags/")] = true
+	}
+	return names, nil
+}
+
+func (t *VersionTag) String() string {
+	return fmt.Sprintf("%s (%s)", t.Tag.GetName(), t.Version)
+}
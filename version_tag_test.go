@@ -0,0 +1,95 @@
+package git
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver"
+)
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		current      string
+		bump         VersionBump
+		prereleaseID string
+		expected     string
+	}{
+		{"1.2.3", BumpPatch, "", "1.2.4"},
+		{"1.2.3", BumpMinor, "", "1.3.0"},
+		{"1.2.3", BumpMajor, "", "2.0.0"},
+		{"1.2.3", BumpPrerelease, "", "1.2.4-rc.1"},
+		{"1.2.3", BumpPrerelease, "beta", "1.2.4-beta.1"},
+		{"1.2.3+build.5", BumpPatch, "", "1.2.4"},
+		// prereleases are released by their bump
+		{"1.3.0-rc.1", BumpPatch, "", "1.3.0"},
+		{"1.3.0-rc.1", BumpMinor, "", "1.3.0"},
+		{"1.3.0-rc.1", BumpMajor, "", "2.0.0"},
+		{"2.0.0-beta", BumpMinor, "", "2.0.0"},
+		{"2.0.0-beta", BumpMajor, "", "2.0.0"},
+		{"1.2.4-rc.3", BumpMinor, "", "1.3.0"},
+		// prerelease increments
+		{"1.3.0-rc.1", BumpPrerelease, "", "1.3.0-rc.2"},
+		{"1.3.0-rc", BumpPrerelease, "rc", "1.3.0-rc.1"},
+		{"1.3.0-beta.2", BumpPrerelease, "rc", "1.3.0-rc.1"},
+		{"1.3.0-rc.1.9", BumpPrerelease, "rc", "1.3.0-rc.1.10"},
+		// a lower prerelease identifier moves to the next patch version
+		{"1.3.0-rc.1", BumpPrerelease, "beta", "1.3.1-beta.1"},
+		{"1.3.0-rc.1", BumpPrerelease, "alpha", "1.3.1-alpha.1"},
+		{"0.0.0", BumpMinor, "", "0.1.0"},
+	}
+	for _, test := range tests {
+		next, err := NextVersion(semver.MustParse(test.current), test.bump, test.prereleaseID)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error %v", test.current, test.bump, err)
+			continue
+		}
+		if next.String() != test.expected {
+			t.Errorf("%s %s: expected %s, got %s", test.current, test.bump, test.expected, next)
+		}
+		if !next.GreaterThan(semver.MustParse(test.current)) {
+			t.Errorf("%s %s: %s is not greater", test.current, test.bump, next)
+		}
+	}
+
+	_, err := NextVersion(semver.MustParse("1.0.0"), VersionBump(42), "")
+	if err == nil {
+		t.Error("expected an error for an invalid bump")
+	}
+}
+
+func TestGetLatestVersion(t *testing.T) {
+	r, git := newTestRepository(t, false)
+	git("commit", "--quiet", "--allow-empty", "-m", "first")
+	for _, tag := range []string{"v1.2.3", "v1.10.0-rc.1", "2.0.0", "v1.3", "other"} {
+		git("tag", tag)
+	}
+	git("checkout", "--quiet", "-b", "side")
+	git("commit", "--quiet", "--allow-empty", "-m", "second")
+	git("tag", "v1.11.0")
+	git("checkout", "--quiet", "main")
+
+	tests := []struct {
+		opts     VersionOptions
+		expected string
+	}{
+		{VersionOptions{Prefix: "v"}, "v1.11.0"},
+		{VersionOptions{Prefix: "v", ReachableFrom: "main"}, "v1.10.0-rc.1"},
+		{VersionOptions{Prefix: "v", ReachableFrom: "main", IgnorePrereleases: true}, "v1.2.3"},
+		{VersionOptions{}, "2.0.0"},
+	}
+	for _, test := range tests {
+		latest, err := r.GetLatestVersion(test.opts)
+		if err != nil {
+			t.Errorf("%+v: unexpected error %v", test.opts, err)
+			continue
+		}
+		if latest.Tag.GetName() != test.expected {
+			t.Errorf("%+v: expected %s, got %s", test.opts, test.expected, latest.Tag.GetName())
+		}
+	}
+
+	_, err := r.GetLatestVersion(VersionOptions{Prefix: "release-"})
+	if !errors.Is(err, ErrNoVersionTag) {
+		t.Errorf("expected ErrNoVersionTag, got %v", err)
+	}
+}